@@ -0,0 +1,18 @@
+package api
+
+import (
+	"go-gin-project/helper/server"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// App is what main runs: the server, the resources it migrates or releases around it, and the logger it
+// reports through.
+type App struct {
+	Server         *server.Server
+	DB             *gorm.DB
+	TracerProvider trace.TracerProvider
+	Logger         *slog.Logger
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"errors"
+	"go-gin-project/api/service"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/responsejson"
+	"go-gin-project/middleware"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeysController struct {
+	apiKeysService service.ApiKeysService
+}
+
+func NewApiKeysController(service service.ApiKeysService) *ApiKeysController {
+	return &ApiKeysController{
+		apiKeysService: service,
+	}
+}
+
+// Create responds with the plaintext key; it is not shown again. The key is limited by the scopes of the caller.
+func (controller *ApiKeysController) Create(ctx *gin.Context) {
+	createApiKeyRequest := data.ApiKeyRequest{}
+	if err := ctx.ShouldBindJSON(&createApiKeyRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	apiKeyResponse, err := controller.apiKeysService.Create(ctx.Request.Context(), createApiKeyRequest, ctx.GetStringSlice(middleware.ScopesKey))
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrForbidden) {
+			responsejson.Forbidden(ctx, err.Error())
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "no-store")
+	responsejson.Success(ctx, "create", apiKeyResponse)
+}
+
+// FindAll and Revoke only reach keys of the caller's tenant unless the caller holds auth.ScopeTenantsAny.
+func (controller *ApiKeysController) FindAll(ctx *gin.Context) {
+	apiKeyResponse, err := controller.apiKeysService.FindAll(ctx.Request.Context(), ctx.GetStringSlice(middleware.ScopesKey))
+	if err != nil {
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.Success(ctx, "read", apiKeyResponse)
+}
+
+func (controller *ApiKeysController) Revoke(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("keyId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.apiKeysService.Revoke(ctx.Request.Context(), id, ctx.GetStringSlice(middleware.ScopesKey)); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "API key not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.Success(ctx, "delete", nil)
+}
@@ -0,0 +1,144 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"go-gin-project/api/controller"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockApiKeysService struct {
+	mock.Mock
+}
+
+func (m *MockApiKeysService) Create(_ context.Context, request data.ApiKeyRequest, granted []string) (data.CreatedApiKeyResponse, error) {
+	args := m.Called(request, granted)
+	return args.Get(0).(data.CreatedApiKeyResponse), args.Error(1)
+}
+
+func (m *MockApiKeysService) FindAll(_ context.Context, granted []string) ([]data.ApiKeyResponse, error) {
+	args := m.Called(granted)
+	return args.Get(0).([]data.ApiKeyResponse), args.Error(1)
+}
+
+func (m *MockApiKeysService) Revoke(_ context.Context, keyId int, granted []string) error {
+	args := m.Called(keyId, granted)
+	return args.Error(0)
+}
+
+func (m *MockApiKeysService) Authenticate(_ context.Context, key string) (data.ApiKeyResponse, error) {
+	args := m.Called(key)
+	return args.Get(0).(data.ApiKeyResponse), args.Error(1)
+}
+
+func setupApiKeysTest() (*MockApiKeysService, *controller.ApiKeysController, *gin.Engine) {
+	mockService := new(MockApiKeysService)
+	controller := controller.NewApiKeysController(mockService)
+	router := setupRouter()
+	return mockService, controller, router
+}
+
+func TestApiKeysController(t *testing.T) {
+	t.Run("should create a key and show the plaintext", func(t *testing.T) {
+		mockService, controller, router := setupApiKeysTest()
+		router.POST("/admin/api-key", controller.Create)
+
+		request := data.ApiKeyRequest{Name: "ci", Scopes: []string{"tags:read"}}
+		response := data.CreatedApiKeyResponse{
+			ApiKeyResponse: data.ApiKeyResponse{Id: 1, Name: "ci", Prefix: "tk_0123abcd", Scopes: []string{"tags:read"}},
+			Key:            "tk_0123abcd_secret",
+		}
+		mockService.On("Create", request, []string(nil)).Return(response, nil)
+
+		req, _ := http.NewRequest("POST", "/admin/api-key", bytes.NewBufferString(`{"name":"ci","scopes":["tags:read"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Contains(t, w.Body.String(), `"key":"tk_0123abcd_secret"`)
+		assert.Contains(t, w.Body.String(), `"prefix":"tk_0123abcd"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an invalid key request", func(t *testing.T) {
+		mockService, controller, router := setupApiKeysTest()
+		router.POST("/admin/api-key", controller.Create)
+
+		mockService.On("Create", mock.Anything, mock.Anything).Return(data.CreatedApiKeyResponse{}, helper.ErrFailedValidation)
+
+		req, _ := http.NewRequest("POST", "/admin/api-key", bytes.NewBufferString(`{"name":""}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should pass the caller's scopes and forbid escalation", func(t *testing.T) {
+		mockService, controller, router := setupApiKeysTest()
+		router.POST("/admin/api-key", func(ctx *gin.Context) {
+			ctx.Set(middleware.ScopesKey, []string{"api-keys:admin"})
+		}, controller.Create)
+
+		mockService.On("Create", data.ApiKeyRequest{Name: "ci", Scopes: []string{"tags:admin"}}, []string{"api-keys:admin"}).
+			Return(data.CreatedApiKeyResponse{}, helper.ErrForbidden)
+
+		req, _ := http.NewRequest("POST", "/admin/api-key", bytes.NewBufferString(`{"name":"ci","scopes":["tags:admin"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should list keys", func(t *testing.T) {
+		mockService, controller, router := setupApiKeysTest()
+		router.GET("/admin/api-key", func(ctx *gin.Context) {
+			ctx.Set(middleware.ScopesKey, []string{"api-keys:admin"})
+		}, controller.FindAll)
+
+		mockService.On("FindAll", []string{"api-keys:admin"}).Return([]data.ApiKeyResponse{{Id: 1, Name: "ci", Prefix: "tk_0123abcd", Scopes: []string{}}}, nil)
+
+		req, _ := http.NewRequest("GET", "/admin/api-key", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"name":"ci"`)
+		assert.NotContains(t, w.Body.String(), `"key"`)
+	})
+
+	t.Run("should revoke a key", func(t *testing.T) {
+		mockService, controller, router := setupApiKeysTest()
+		router.DELETE("/admin/api-key/:keyId", func(ctx *gin.Context) {
+			ctx.Set(middleware.ScopesKey, []string{"api-keys:admin"})
+		}, controller.Revoke)
+
+		mockService.On("Revoke", 1, []string{"api-keys:admin"}).Return(nil)
+		mockService.On("Revoke", 2, []string{"api-keys:admin"}).Return(helper.ErrNotFound)
+
+		for path, code := range map[string]int{
+			"/admin/api-key/1":   http.StatusOK,
+			"/admin/api-key/2":   http.StatusNotFound,
+			"/admin/api-key/abc": http.StatusBadRequest,
+		} {
+			req, _ := http.NewRequest("DELETE", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, code, w.Code, path)
+		}
+	})
+}
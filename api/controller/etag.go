@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"go-gin-project/helper/responsejson"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag renders a row's id and version as a strong entity tag, "<id>-<version>". The id keeps rows that
+// share a version apart, for instance two tags a name resolved to before and after a rename or merge.
+func versionETag(id int, version int) string {
+	return responsejson.StrongETag(strconv.Itoa(id) + "-" + strconv.Itoa(version))
+}
+
+// lastModified parses an RFC 3339 response timestamp, yielding the zero time when it is absent.
+func lastModified(timestamp string) time.Time {
+	value, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}
+	}
+	return value
+}
+
+// ifMatchVersions parses the If-Match header (RFC 9110 section 13.1.1) into the versions of row id it lists.
+// unconditional is set when there is no header or it is "*". If-Match compares strongly, so weak entity tags
+// and tags not rendered by versionETag for id can never match and are left out. ok is false when the header is
+// malformed.
+func ifMatchVersions(ctx *gin.Context, id int) (versions []int, unconditional bool, ok bool) {
+	header := strings.TrimSpace(strings.Join(ctx.Request.Header.Values("If-Match"), ","))
+	if header == "" || header == "*" {
+		return nil, true, true
+	}
+
+	for _, element := range strings.Split(header, ",") {
+		tag := strings.TrimSpace(element)
+		if tag == "" {
+			continue
+		}
+		opaque, weak := strings.CutPrefix(tag, "W/")
+		if len(opaque) < 2 || !strings.HasPrefix(opaque, `"`) || !strings.HasSuffix(opaque, `"`) ||
+			strings.Contains(opaque[1:len(opaque)-1], `"`) {
+			return nil, false, false
+		}
+		if weak {
+			continue
+		}
+		tagId, tagVersion, _ := strings.Cut(opaque[1:len(opaque)-1], "-")
+		if tagId != strconv.Itoa(id) {
+			continue
+		}
+		version, err := strconv.Atoi(tagVersion)
+		if err == nil && version > 0 {
+			versions = append(versions, version)
+		}
+	}
+	return versions, false, true
+}
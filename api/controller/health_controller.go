@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"go-gin-project/helper/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	registry *health.Registry
+}
+
+func NewHealthController(registry *health.Registry) *HealthController {
+	return &HealthController{
+		registry: registry,
+	}
+}
+
+// Liveness answers as long as the process can serve requests; it checks no dependencies.
+func (controller *HealthController) Liveness(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// Readiness runs every registered check and answers 503 Service Unavailable when any of them is down.
+func (controller *HealthController) Readiness(ctx *gin.Context) {
+	report := controller.registry.Run(ctx.Request.Context())
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(status, report)
+}
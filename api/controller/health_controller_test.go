@@ -0,0 +1,63 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-gin-project/api/controller"
+	"go-gin-project/helper/health"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthController(t *testing.T) {
+	databaseUp := true
+	registry := health.NewRegistry(time.Second)
+	registry.Register("database", func(ctx context.Context) error {
+		if !databaseUp {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	controller := controller.NewHealthController(registry)
+	router := setupRouter()
+	router.GET("/healthz", controller.Liveness)
+	router.GET("/readyz", controller.Readiness)
+
+	t.Run("should be ready while every check passes", func(t *testing.T) {
+		databaseUp = true
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		report := health.Report{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusUp, report.Status)
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	})
+
+	t.Run("should not be ready when a check fails but stay alive", func(t *testing.T) {
+		databaseUp = false
+		req, _ := http.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		report := health.Report{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, health.StatusDown, report.Status)
+		assert.Equal(t, "connection refused", report.Checks["database"].Error)
+
+		req, _ = http.NewRequest("GET", "/healthz", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+	})
+}
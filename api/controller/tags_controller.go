@@ -6,6 +6,8 @@ import (
 	"go-gin-project/data"
 	"go-gin-project/helper"
 	"go-gin-project/helper/responsejson"
+	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -29,12 +31,16 @@ func (controller *TagsController) Create(ctx *gin.Context) {
 		return
 	}
 
-	err = controller.tagsService.Create(createTagsRequest)
+	err = controller.tagsService.Create(ctx.Request.Context(), createTagsRequest)
 	if err != nil {
 		if errors.Is(err, helper.ErrFailedValidation) {
 			responsejson.BadRequest(ctx, err)
 			return
 		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Tag name already exists")
+			return
+		}
 		responsejson.InternalServerError(ctx, err)
 		return
 	}
@@ -42,18 +48,49 @@ func (controller *TagsController) Create(ctx *gin.Context) {
 }
 
 func (controller *TagsController) FindAll(ctx *gin.Context) {
-	tagResponse, err := controller.tagsService.FindAll()
+	query := data.TagQuery{}
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	query.Attributes = data.AttributeFilters(ctx.Request.URL.Query())
+
+	if query.UsesCursor() {
+		tagResponse, meta, err := controller.tagsService.FindAllByCursor(ctx.Request.Context(), query)
+		if err != nil {
+			if errors.Is(err, helper.ErrFailedValidation) {
+				responsejson.BadRequest(ctx, err)
+				return
+			}
+			responsejson.InternalServerError(ctx, err)
+			return
+		}
+		responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, meta)
+		return
+	}
+
+	tagResponse, meta, err := controller.tagsService.FindAll(ctx.Request.Context(), query)
 	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
 		responsejson.InternalServerError(ctx, err)
 		return
 	}
-	responsejson.Success(ctx, "read", tagResponse)
+	responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, meta)
 }
 
 func (controller *TagsController) FindById(ctx *gin.Context) {
 	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
 
-	tagResponse, err := controller.tagsService.FindById(tagId)
+	tagResponse, err := controller.tagsService.FindById(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, helper.ErrNotFound) {
 			responsejson.NotFound(ctx, err.Error())
@@ -62,23 +99,206 @@ func (controller *TagsController) FindById(ctx *gin.Context) {
 		responsejson.InternalServerError(ctx, err)
 		return
 	}
-	responsejson.Success(ctx, "read", tagResponse)
+	responsejson.ConditionalSuccess(ctx, tagResponse, versionETag(tagResponse.Id, tagResponse.Version), lastModified(tagResponse.UpdatedAt))
+}
+
+func (controller *TagsController) FindByName(ctx *gin.Context) {
+	name := ctx.Param("name")
+
+	tagResponse, err := controller.tagsService.FindByName(ctx.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, err.Error())
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccess(ctx, tagResponse, versionETag(tagResponse.Id, tagResponse.Version), lastModified(tagResponse.UpdatedAt))
+}
+
+func (controller *TagsController) FindChildren(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	tagResponse, err := controller.tagsService.FindChildren(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, nil)
+}
+
+func (controller *TagsController) FindAncestors(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	tagResponse, err := controller.tagsService.FindAncestors(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, nil)
+}
+
+func (controller *TagsController) FindTree(ctx *gin.Context) {
+	query := data.TagTreeQuery{}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	tree, meta, err := controller.tagsService.FindTree(ctx.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, tree, meta)
 }
 
 func (controller *TagsController) Update(ctx *gin.Context) {
 	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
 	updateTagsRequest := data.TagRequest{}
-	err := ctx.ShouldBindJSON(&updateTagsRequest)
+	err = ctx.ShouldBindJSON(&updateTagsRequest)
+	if err != nil {
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	version, ok := controller.ifMatchVersion(ctx, id)
+	if !ok {
+		responsejson.PreconditionFailed(ctx, "If-Match does not match the current tag version")
+		return
+	}
+	err = controller.tagsService.Update(ctx.Request.Context(), id, updateTagsRequest, version)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, err.Error())
+			return
+		}
+		if errors.Is(err, helper.ErrPreconditionFailed) {
+			responsejson.PreconditionFailed(ctx, "Tag has been modified, fetch it again before updating")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Tag name already exists")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "update", nil)
+}
+
+func (controller *TagsController) Patch(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
 	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	contentType := ctx.ContentType()
+	if contentType != data.MergePatchContentType && contentType != data.JSONPatchContentType {
+		responsejson.UnsupportedMediaType(ctx, "PATCH accepts "+data.MergePatchContentType+" or "+data.JSONPatchContentType)
+		return
+	}
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	version, ok := controller.ifMatchVersion(ctx, id)
+	if !ok {
+		responsejson.PreconditionFailed(ctx, "If-Match does not match the current tag version")
+		return
+	}
+
+	err = controller.tagsService.Patch(ctx.Request.Context(), id, contentType, patch, version)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, err.Error())
+			return
+		}
+		if errors.Is(err, helper.ErrInvalidPatch) {
+			responsejson.UnprocessableEntity(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Tag name already exists")
+			return
+		}
+		if errors.Is(err, helper.ErrPreconditionFailed) {
+			responsejson.PreconditionFailed(ctx, "Tag has been modified, fetch it again before updating")
+			return
+		}
 		responsejson.InternalServerError(ctx, err)
 		return
 	}
-	err = controller.tagsService.Update(tagId, updateTagsRequest)
+
+	responsejson.Success(ctx, "update", nil)
+}
+
+func (controller *TagsController) Move(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	moveTagRequest := data.MoveTagRequest{}
+	if err := ctx.ShouldBindJSON(&moveTagRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	version, ok := controller.ifMatchVersion(ctx, id)
+	if !ok {
+		responsejson.PreconditionFailed(ctx, "If-Match does not match the current tag version")
+		return
+	}
+
+	err = controller.tagsService.Move(ctx.Request.Context(), id, moveTagRequest.ParentId, version)
 	if err != nil {
 		if errors.Is(err, helper.ErrNotFound) {
 			responsejson.NotFound(ctx, err.Error())
 			return
 		}
+		if errors.Is(err, helper.ErrPreconditionFailed) {
+			responsejson.PreconditionFailed(ctx, "Tag has been modified, fetch it again before updating")
+			return
+		}
 		if errors.Is(err, helper.ErrFailedValidation) {
 			responsejson.BadRequest(ctx, err)
 			return
@@ -90,6 +310,103 @@ func (controller *TagsController) Update(ctx *gin.Context) {
 	responsejson.Success(ctx, "update", nil)
 }
 
+func (controller *TagsController) AddAlias(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	aliasRequest := data.AliasRequest{}
+	if err := ctx.ShouldBindJSON(&aliasRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	err = controller.tagsService.AddAlias(ctx.Request.Context(), id, aliasRequest)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Name is already used by a tag or alias")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "create", nil)
+}
+
+func (controller *TagsController) DeleteAlias(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.tagsService.DeleteAlias(ctx.Request.Context(), id, ctx.Param("alias")); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Alias not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "delete", nil)
+}
+
+func (controller *TagsController) Merge(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	mergeTagRequest := data.MergeTagRequest{}
+	if err := ctx.ShouldBindJSON(&mergeTagRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	version, ok := controller.ifMatchVersion(ctx, id)
+	if !ok {
+		responsejson.PreconditionFailed(ctx, "If-Match does not match the current tag version")
+		return
+	}
+
+	err = controller.tagsService.Merge(ctx.Request.Context(), id, mergeTagRequest, version)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Target or source tag not found")
+			return
+		}
+		if errors.Is(err, helper.ErrPreconditionFailed) {
+			responsejson.PreconditionFailed(ctx, "Tag has been modified, fetch it again before merging")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Name is already used by a tag or alias")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "update", nil)
+}
+
 func (controller *TagsController) Delete(ctx *gin.Context) {
 	tagId := ctx.Param("tagId")
 	id, err := strconv.Atoi(tagId)
@@ -97,15 +414,311 @@ func (controller *TagsController) Delete(ctx *gin.Context) {
 		responsejson.BadRequest(ctx, err)
 		return
 	}
+	permanent, err := strconv.ParseBool(ctx.DefaultQuery("permanent", "false"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
 
-	if err := controller.tagsService.Delete(id); err != nil {
+	version, ok := controller.ifMatchVersion(ctx, id)
+	if !ok {
+		responsejson.PreconditionFailed(ctx, "If-Match does not match the current tag version")
+		return
+	}
+
+	strategy := ctx.DefaultQuery("strategy", data.DeleteReject)
+	if permanent {
+		err = controller.tagsService.DeletePermanently(ctx.Request.Context(), id, version, strategy)
+	} else {
+		err = controller.tagsService.Delete(ctx.Request.Context(), id, version, strategy)
+	}
+	if err != nil {
 		if errors.Is(err, helper.ErrNotFound) {
 			responsejson.NotFound(ctx, "Tag not found")
 			return
 		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		if errors.Is(err, helper.ErrHasChildren) {
+			responsejson.Conflict(ctx, "Tag has child tags, delete it with strategy=cascade or strategy=reparent")
+			return
+		}
+		if errors.Is(err, helper.ErrPreconditionFailed) {
+			responsejson.PreconditionFailed(ctx, "Tag has been modified, fetch it again before deleting")
+			return
+		}
 		responsejson.InternalServerError(ctx, err)
 		return
 	}
 
 	responsejson.Success(ctx, "delete", nil)
 }
+
+func (controller *TagsController) FindTrash(ctx *gin.Context) {
+	query := data.TagQuery{}
+	err := ctx.ShouldBindQuery(&query)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	query.Attributes = data.AttributeFilters(ctx.Request.URL.Query())
+
+	tagResponse, meta, err := controller.tagsService.FindTrash(ctx.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, meta)
+}
+
+func (controller *TagsController) Restore(ctx *gin.Context) {
+	tagId := ctx.Param("tagId")
+	id, err := strconv.Atoi(tagId)
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.tagsService.Restore(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found in trash")
+			return
+		}
+		if errors.Is(err, helper.ErrConflict) {
+			responsejson.Conflict(ctx, "Tag name already exists")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "update", nil)
+}
+
+func (controller *TagsController) PurgeTrash(ctx *gin.Context) {
+	purgeResponse, err := controller.tagsService.PurgeTrash(ctx.Request.Context())
+	if err != nil {
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "delete", purgeResponse)
+}
+
+func (controller *TagsController) BulkCreate(ctx *gin.Context) {
+	partial, err := strconv.ParseBool(ctx.DefaultQuery("partial", "false"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	createTagsRequest := []data.TagRequest{}
+	if err := ctx.ShouldBindJSON(&createTagsRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	results, err := controller.tagsService.BulkCreate(ctx.Request.Context(), createTagsRequest, partial)
+	bulkResponse(ctx, http.StatusCreated, results, err)
+}
+
+func (controller *TagsController) BulkUpdate(ctx *gin.Context) {
+	partial, err := strconv.ParseBool(ctx.DefaultQuery("partial", "false"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	updateTagsRequest := []data.BulkTagUpdateRequest{}
+	if err := ctx.ShouldBindJSON(&updateTagsRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	results, err := controller.tagsService.BulkUpdate(ctx.Request.Context(), updateTagsRequest, partial)
+	bulkResponse(ctx, http.StatusOK, results, err)
+}
+
+func (controller *TagsController) BulkDelete(ctx *gin.Context) {
+	partial, err := strconv.ParseBool(ctx.DefaultQuery("partial", "false"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	tagIds := []int{}
+	if err := ctx.ShouldBindJSON(&tagIds); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	results, err := controller.tagsService.BulkDelete(ctx.Request.Context(), tagIds, partial)
+	bulkResponse(ctx, http.StatusOK, results, err)
+}
+
+// bulkResponse answers with the per-item results. A failed all-or-nothing batch gets the status of the error
+// that aborted it. A partial batch answers 207 Multi-Status when some items failed and 422 Unprocessable Entity
+// when all of them did.
+func bulkResponse(ctx *gin.Context, successCode int, results []data.BulkResult, err error) {
+	if err != nil && results == nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	code := successCode
+	meta := data.NewBulkMeta(results)
+	switch {
+	case err == nil && meta.Failed == 0:
+	case err == nil && meta.Succeeded == 0:
+		code = http.StatusUnprocessableEntity
+	case err == nil:
+		code = http.StatusMultiStatus
+	case errors.Is(err, helper.ErrFailedValidation):
+		code = http.StatusBadRequest
+	case errors.Is(err, helper.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, helper.ErrConflict), errors.Is(err, helper.ErrHasChildren):
+		code = http.StatusConflict
+	case errors.Is(err, helper.ErrPreconditionFailed):
+		code = http.StatusPreconditionFailed
+	default:
+		code = http.StatusInternalServerError
+	}
+	responsejson.Bulk(ctx, code, results, meta)
+}
+
+func (controller *TagsController) Attach(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("tagId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	resource := data.ResourceRef{}
+	if err := ctx.ShouldBindUri(&resource); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.tagsService.Attach(ctx.Request.Context(), id, resource); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "create", nil)
+}
+
+func (controller *TagsController) Detach(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("tagId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	resource := data.ResourceRef{}
+	if err := ctx.ShouldBindUri(&resource); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.tagsService.Detach(ctx.Request.Context(), id, resource); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag is not attached to the resource")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "delete", nil)
+}
+
+func (controller *TagsController) FindByResource(ctx *gin.Context) {
+	resource := data.ResourceRef{}
+	if err := ctx.ShouldBindUri(&resource); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	tagResponse, err := controller.tagsService.FindByResource(ctx.Request.Context(), resource)
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, tagResponse, nil)
+}
+
+func (controller *TagsController) FindResources(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("tagId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	query := data.TaggingQuery{}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	resources, meta, err := controller.tagsService.FindResources(ctx.Request.Context(), id, query)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Tag not found")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, resources, meta)
+}
+
+// ifMatchVersion resolves the If-Match header of a write to tagId into the version the write is conditional on,
+// 0 for an unconditional one. Of several listed versions the current one is taken; the write still checks it,
+// so a concurrent change fails it. ok is false when the header cannot match the current version.
+func (controller *TagsController) ifMatchVersion(ctx *gin.Context, tagId int) (version int, ok bool) {
+	versions, unconditional, ok := ifMatchVersions(ctx, tagId)
+	if unconditional || !ok {
+		return 0, ok
+	}
+	if len(versions) == 0 {
+		return 0, false
+	}
+	if len(versions) == 1 {
+		return versions[0], true
+	}
+
+	tag, err := controller.tagsService.FindById(ctx.Request.Context(), tagId)
+	if err != nil {
+		// Let the write report the missing tag, or whatever else went wrong.
+		return versions[0], true
+	}
+	if !slices.Contains(versions, tag.Version) {
+		return 0, false
+	}
+	return tag.Version, true
+}
@@ -2,12 +2,15 @@ package controller_test
 
 import (
 	"bytes"
+	"context"
 	"go-gin-project/api/controller"
 	"go-gin-project/data"
 	"go-gin-project/helper"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"errors"
 
@@ -20,31 +23,145 @@ type MockTagsService struct {
 	mock.Mock
 }
 
-func (m *MockTagsService) Create(request data.TagRequest) error {
+func (m *MockTagsService) Create(_ context.Context, request data.TagRequest) error {
 	args := m.Called(request)
 	return args.Error(0)
 }
 
-func (m *MockTagsService) FindAll() ([]data.TagResponse, error) {
-	args := m.Called()
-	return args.Get(0).([]data.TagResponse), args.Error(1)
+func (m *MockTagsService) FindAll(_ context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error) {
+	args := m.Called(query)
+	return args.Get(0).([]data.TagResponse), args.Get(1).(data.PageMeta), args.Error(2)
+}
+
+func (m *MockTagsService) FindAllByCursor(_ context.Context, query data.TagQuery) ([]data.TagResponse, data.CursorMeta, error) {
+	args := m.Called(query)
+	return args.Get(0).([]data.TagResponse), args.Get(1).(data.CursorMeta), args.Error(2)
 }
 
-func (m *MockTagsService) FindById(tagId string) (data.TagResponse, error) {
+func (m *MockTagsService) FindById(_ context.Context, tagId int) (data.TagResponse, error) {
 	args := m.Called(tagId)
 	return args.Get(0).(data.TagResponse), args.Error(1)
 }
 
-func (m *MockTagsService) Update(tagId string, request data.TagRequest) error {
-	args := m.Called(tagId, request)
+func (m *MockTagsService) Update(_ context.Context, tagId int, request data.TagRequest, version int) error {
+	args := m.Called(tagId, request, version)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) Patch(_ context.Context, tagId int, contentType string, patch []byte, version int) error {
+	args := m.Called(tagId, contentType, patch, version)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) FindByName(_ context.Context, name string) (data.TagResponse, error) {
+	args := m.Called(name)
+	return args.Get(0).(data.TagResponse), args.Error(1)
+}
+
+func (m *MockTagsService) FindChildren(_ context.Context, tagId int) ([]data.TagResponse, error) {
+	args := m.Called(tagId)
+	tags, _ := args.Get(0).([]data.TagResponse)
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsService) FindAncestors(_ context.Context, tagId int) ([]data.TagResponse, error) {
+	args := m.Called(tagId)
+	tags, _ := args.Get(0).([]data.TagResponse)
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsService) FindTree(_ context.Context, query data.TagTreeQuery) ([]data.TagTreeNode, data.TagTreeMeta, error) {
+	args := m.Called(query)
+	tree, _ := args.Get(0).([]data.TagTreeNode)
+	meta, _ := args.Get(1).(data.TagTreeMeta)
+	return tree, meta, args.Error(2)
+}
+
+func (m *MockTagsService) Move(_ context.Context, tagId int, parentId *int, version int) error {
+	args := m.Called(tagId, parentId, version)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) Delete(_ context.Context, tagId int, version int, strategy string) error {
+	args := m.Called(tagId, version, strategy)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) DeletePermanently(_ context.Context, tagId int, version int, strategy string) error {
+	args := m.Called(tagId, version, strategy)
 	return args.Error(0)
 }
 
-func (m *MockTagsService) Delete(tagId int) error {
+func (m *MockTagsService) FindTrash(_ context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error) {
+	args := m.Called(query)
+	return args.Get(0).([]data.TagResponse), args.Get(1).(data.PageMeta), args.Error(2)
+}
+
+func (m *MockTagsService) Restore(_ context.Context, tagId int) error {
 	args := m.Called(tagId)
 	return args.Error(0)
 }
 
+func (m *MockTagsService) PurgeTrash(_ context.Context) (data.PurgeResponse, error) {
+	args := m.Called()
+	return args.Get(0).(data.PurgeResponse), args.Error(1)
+}
+
+func (m *MockTagsService) BulkCreate(_ context.Context, tags []data.TagRequest, partial bool) ([]data.BulkResult, error) {
+	args := m.Called(tags, partial)
+	results, _ := args.Get(0).([]data.BulkResult)
+	return results, args.Error(1)
+}
+
+func (m *MockTagsService) BulkUpdate(_ context.Context, tags []data.BulkTagUpdateRequest, partial bool) ([]data.BulkResult, error) {
+	args := m.Called(tags, partial)
+	results, _ := args.Get(0).([]data.BulkResult)
+	return results, args.Error(1)
+}
+
+func (m *MockTagsService) BulkDelete(_ context.Context, tagIds []int, partial bool) ([]data.BulkResult, error) {
+	args := m.Called(tagIds, partial)
+	results, _ := args.Get(0).([]data.BulkResult)
+	return results, args.Error(1)
+}
+
+func (m *MockTagsService) AddAlias(_ context.Context, tagId int, alias data.AliasRequest) error {
+	args := m.Called(tagId, alias)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) DeleteAlias(_ context.Context, tagId int, name string) error {
+	args := m.Called(tagId, name)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) Merge(_ context.Context, tagId int, merge data.MergeTagRequest, version int) error {
+	args := m.Called(tagId, merge, version)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) Attach(_ context.Context, tagId int, resource data.ResourceRef) error {
+	args := m.Called(tagId, resource)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) Detach(_ context.Context, tagId int, resource data.ResourceRef) error {
+	args := m.Called(tagId, resource)
+	return args.Error(0)
+}
+
+func (m *MockTagsService) FindByResource(_ context.Context, resource data.ResourceRef) ([]data.TagResponse, error) {
+	args := m.Called(resource)
+	tags, _ := args.Get(0).([]data.TagResponse)
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsService) FindResources(_ context.Context, tagId int, query data.TaggingQuery) ([]data.ResourceResponse, data.PageMeta, error) {
+	args := m.Called(tagId, query)
+	resources, _ := args.Get(0).([]data.ResourceResponse)
+	return resources, args.Get(1).(data.PageMeta), args.Error(2)
+}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -108,6 +225,24 @@ func TestCreateTag(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 	})
 
+	t.Run("should return conflict when tag name exists", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags", controller.Create)
+
+		mockService.On("Create", mock.Anything).Return(helper.ErrConflict)
+
+		requestBody := `{"name": "Golang"}`
+		req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Conflict"`)
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("should handle validation error", func(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.POST("/tags", controller.Create)
@@ -135,7 +270,7 @@ func TestFindAllTags(t *testing.T) {
 			{Id: 1, Name: "Tag1"},
 			{Id: 2, Name: "Tag2"},
 		}
-		mockService.On("FindAll").Return(expectedTags, nil)
+		mockService.On("FindAll", data.TagQuery{}).Return(expectedTags, data.NewPageMeta(1, 20, 2), nil)
 
 		req, _ := http.NewRequest("GET", "/tags", nil)
 		w := httptest.NewRecorder()
@@ -144,6 +279,143 @@ func TestFindAllTags(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"name":"Tag1"`)
+		assert.Contains(t, w.Body.String(), `"meta":{"page":1,"page_size":20,"total":2,"total_pages":1}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not modified for a current collection ETag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		expectedTags := []data.TagResponse{{Id: 1, Name: "Tag1"}}
+		mockService.On("FindAll", data.TagQuery{}).Return(expectedTags, data.NewPageMeta(1, 20, 1), nil).Twice()
+
+		req, _ := http.NewRequest("GET", "/tags", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		etag := w.Header().Get("ETag")
+		assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+		req, _ = http.NewRequest("GET", "/tags", nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass query options to service", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		expectedQuery := data.TagQuery{Page: 2, PageSize: 5, Sort: "-id", NameContains: "go", NamePrefix: "g"}
+		mockService.On("FindAll", expectedQuery).Return([]data.TagResponse{}, data.NewPageMeta(2, 5, 6), nil)
+
+		req, _ := http.NewRequest("GET", "/tags?page=2&page_size=5&sort=-id&name_contains=go&name_prefix=g", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total_pages":2`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should parse timestamp filters", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		expectedQuery := data.TagQuery{
+			CreatedAfter: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedSince: time.Date(2024, 5, 2, 8, 0, 0, 0, time.FixedZone("", 8*3600)),
+		}
+		mockService.On("FindAll", mock.MatchedBy(func(query data.TagQuery) bool {
+			return query.CreatedAfter.Equal(expectedQuery.CreatedAfter) && query.UpdatedSince.Equal(expectedQuery.UpdatedSince)
+		})).Return([]data.TagResponse{}, data.NewPageMeta(1, 20, 0), nil)
+
+		req, _ := http.NewRequest("GET", "/tags?created_after=2024-05-01T00:00:00Z&updated_since=2024-05-02T08:00:00%2B08:00", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for malformed timestamp", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		req, _ := http.NewRequest("GET", "/tags?created_after=yesterday", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for malformed page", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		req, _ := http.NewRequest("GET", "/tags?page=abc", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request when service rejects query", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		mockService.On("FindAll", data.TagQuery{Sort: "created"}).Return([]data.TagResponse{}, data.PageMeta{}, helper.ErrFailedValidation)
+
+		req, _ := http.NewRequest("GET", "/tags?sort=created", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should use cursor pagination when requested", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		expectedQuery := data.TagQuery{PageSize: 1, Pagination: "cursor"}
+		mockService.On("FindAllByCursor", expectedQuery).
+			Return([]data.TagResponse{{Id: 1, Name: "Tag1"}}, data.CursorMeta{PageSize: 1, NextCursor: "abc.def"}, nil)
+
+		req, _ := http.NewRequest("GET", "/tags?pagination=cursor&page_size=1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"next_cursor":"abc.def"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid cursor", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		mockService.On("FindAllByCursor", data.TagQuery{Cursor: "bogus"}).
+			Return([]data.TagResponse{}, data.CursorMeta{}, helper.ErrFailedValidation)
+
+		req, _ := http.NewRequest("GET", "/tags?cursor=bogus", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
@@ -151,7 +423,7 @@ func TestFindAllTags(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.GET("/tags", controller.FindAll)
 
-		mockService.On("FindAll").Return([]data.TagResponse{}, errors.New("unexpected error"))
+		mockService.On("FindAll", data.TagQuery{}).Return([]data.TagResponse{}, data.PageMeta{}, errors.New("unexpected error"))
 
 		req, _ := http.NewRequest("GET", "/tags", nil)
 		w := httptest.NewRecorder()
@@ -168,8 +440,8 @@ func TestFindById(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.GET("/tags/:tagId", controller.FindById)
 
-		expectedTag := data.TagResponse{Id: 1, Name: "Tag1"}
-		mockService.On("FindById", "1").Return(expectedTag, nil)
+		expectedTag := data.TagResponse{Id: 1, Name: "Tag1", Version: 3}
+		mockService.On("FindById", 1).Return(expectedTag, nil)
 
 		req, _ := http.NewRequest("GET", "/tags/1", nil)
 		w := httptest.NewRecorder()
@@ -178,6 +450,43 @@ func TestFindById(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"name":"Tag1"`)
+		assert.Equal(t, `"1-3"`, w.Header().Get("ETag"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not modified for a current ETag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId", controller.FindById)
+
+		expectedTag := data.TagResponse{Id: 1, Name: "Tag1", Version: 3, UpdatedAt: "2024-05-01T10:00:00Z"}
+		mockService.On("FindById", 1).Return(expectedTag, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/1", nil)
+		req.Header.Set("If-None-Match", `"1-3"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, "Wed, 01 May 2024 10:00:00 GMT", w.Header().Get("Last-Modified"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not modified when unchanged since", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId", controller.FindById)
+
+		expectedTag := data.TagResponse{Id: 1, Name: "Tag1", Version: 3, UpdatedAt: "2024-05-01T10:00:00Z"}
+		mockService.On("FindById", 1).Return(expectedTag, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/1", nil)
+		req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 10:00:00 GMT")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
@@ -185,7 +494,7 @@ func TestFindById(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.GET("/tags/:tagId", controller.FindById)
 
-		mockService.On("FindById", "999").Return(data.TagResponse{}, helper.ErrNotFound)
+		mockService.On("FindById", 999).Return(data.TagResponse{}, helper.ErrNotFound)
 
 		req, _ := http.NewRequest("GET", "/tags/999", nil)
 		w := httptest.NewRecorder()
@@ -200,7 +509,7 @@ func TestFindById(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.GET("/tags/:tagId", controller.FindById)
 
-		mockService.On("FindById", "1").Return(data.TagResponse{}, errors.New("unexpected error"))
+		mockService.On("FindById", 1).Return(data.TagResponse{}, errors.New("unexpected error"))
 
 		req, _ := http.NewRequest("GET", "/tags/1", nil)
 		w := httptest.NewRecorder()
@@ -211,6 +520,27 @@ func TestFindById(t *testing.T) {
 		assert.Contains(t, w.Body.String(), `"status":"Internal Server Error"`)
 		mockService.AssertExpectations(t)
 	})
+
+	t.Run("should reject a non numeric id", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId", controller.FindById)
+		router.PUT("/tags/:tagId", controller.Update)
+		router.PATCH("/tags/:tagId", controller.Patch)
+		router.PUT("/tags/:tagId/parent", controller.Move)
+
+		for _, target := range []string{"GET /tags/1%20OR%201=1", "PUT /tags/1%20OR%201=1", "PATCH /tags/1%20OR%201=1",
+			"PUT /tags/1%20OR%201=1/parent"} {
+			method, path, _ := strings.Cut(target, " ")
+			req, _ := http.NewRequest(method, path, bytes.NewBufferString(`{"name":"Tag1"}`))
+			req.Header.Set("Content-Type", data.MergePatchContentType)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		}
+		mockService.AssertNotCalled(t, "FindById", mock.Anything)
+	})
 }
 
 func TestUpdateTag(t *testing.T) {
@@ -223,7 +553,7 @@ func TestUpdateTag(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
-		mockService.On("Update", "1", mock.Anything).Return(nil)
+		mockService.On("Update", 1, mock.Anything, 0).Return(nil)
 
 		router.ServeHTTP(w, req)
 
@@ -241,7 +571,7 @@ func TestUpdateTag(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
-		mockService.On("Update", "1", mock.Anything).Return(helper.ErrFailedValidation)
+		mockService.On("Update", 1, mock.Anything, 0).Return(helper.ErrFailedValidation)
 
 		router.ServeHTTP(w, req)
 
@@ -259,7 +589,7 @@ func TestUpdateTag(t *testing.T) {
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
-		mockService.On("Update", "999", mock.Anything).Return(helper.ErrNotFound)
+		mockService.On("Update", 999, mock.Anything, 0).Return(helper.ErrNotFound)
 
 		router.ServeHTTP(w, req)
 
@@ -268,93 +598,418 @@ func TestUpdateTag(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should handle invalid JSON", func(t *testing.T) {
+	t.Run("should pass If-Match version to service", func(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.PUT("/tags/:tagId", controller.Update)
 
-		requestBody := `{"name":`
+		requestBody := `{"name": "Updated Tag"}`
 		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-4"`)
 		w := httptest.NewRecorder()
 
+		mockService.On("Update", 1, mock.Anything, 4).Return(nil)
+
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), `"status":"Internal Server Error"`)
+		assert.Equal(t, http.StatusOK, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should return internal server error when service fails", func(t *testing.T) {
+	t.Run("should return precondition failed on version mismatch", func(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.PUT("/tags/:tagId", controller.Update)
 
 		requestBody := `{"name": "Updated Tag"}`
 		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-2"`)
 		w := httptest.NewRecorder()
 
-		mockService.On("Update", "1", mock.Anything).Return(errors.New("unexpected error"))
+		mockService.On("Update", 1, mock.Anything, 2).Return(helper.ErrPreconditionFailed)
 
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		assert.Contains(t, w.Body.String(), `"status":"Internal Server Error"`)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Precondition Failed"`)
 		mockService.AssertExpectations(t)
 	})
-}
 
-func TestDeleteTag(t *testing.T) {
-	t.Run("should delete tag successfully", func(t *testing.T) {
+	t.Run("should return precondition failed for weak If-Match", func(t *testing.T) {
 		mockService, controller, router := setupTest()
-		router.DELETE("/tags/:tagId", controller.Delete)
+		router.PUT("/tags/:tagId", controller.Update)
+
+		requestBody := `{"name": "Updated Tag"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `W/"1-2"`)
+		w := httptest.NewRecorder()
 
-		mockService.On("Delete", 1).Return(nil)
+		router.ServeHTTP(w, req)
 
-		req, _ := http.NewRequest("DELETE", "/tags/1", nil)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should treat If-Match wildcard as unconditional", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/tags/:tagId", controller.Update)
+
+		requestBody := `{"name": "Updated Tag"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "*")
 		w := httptest.NewRecorder()
 
+		mockService.On("Update", 1, mock.Anything, 0).Return(nil)
+
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Contains(t, w.Body.String(), `"status":"Successfully deleted"`)
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should return not found when tag doesn't exist", func(t *testing.T) {
+	t.Run("should take the current version from an If-Match list", func(t *testing.T) {
 		mockService, controller, router := setupTest()
-		router.DELETE("/tags/:tagId", controller.Delete)
-
-		mockService.On("Delete", 999).Return(helper.ErrNotFound)
+		router.PUT("/tags/:tagId", controller.Update)
 
-		req, _ := http.NewRequest("DELETE", "/tags/999", nil)
+		requestBody := `{"name": "Updated Tag"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"1-3", W/"1-4", "1-5"`)
 		w := httptest.NewRecorder()
 
+		mockService.On("FindById", 1).Return(data.TagResponse{Id: 1, Name: "Tag1", Version: 5}, nil)
+		mockService.On("Update", 1, mock.Anything, 5).Return(nil)
+
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, http.StatusOK, w.Code)
 		mockService.AssertExpectations(t)
 	})
 
-	t.Run("should handle invalid ID format", func(t *testing.T) {
+	t.Run("should return precondition failed when no listed version is current", func(t *testing.T) {
 		mockService, controller, router := setupTest()
-		router.DELETE("/tags/:tagId", controller.Delete)
+		router.PUT("/tags/:tagId", controller.Update)
 
-		req, _ := http.NewRequest("DELETE", "/tags/invalid-id", nil)
+		requestBody := `{"name": "Updated Tag"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Add("If-Match", `"1-3"`)
+		req.Header.Add("If-Match", `"1-4"`)
 		w := httptest.NewRecorder()
 
+		mockService.On("FindById", 1).Return(data.TagResponse{Id: 1, Name: "Tag1", Version: 5}, nil)
+
 		router.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		assert.Contains(t, w.Body.String(), `"status":"Bad Request"`)
-		mockService.AssertExpectations(t)
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should return internal server error when service fails", func(t *testing.T) {
+	t.Run("should use the only strong version of an If-Match list", func(t *testing.T) {
 		mockService, controller, router := setupTest()
 		router.DELETE("/tags/:tagId", controller.Delete)
 
-		mockService.On("Delete", 1).Return(errors.New("unexpected error"))
-
+		req, _ := http.NewRequest("DELETE", "/tags/1", nil)
+		req.Header.Set("If-Match", `W/"1-5", "1-4"`)
+		w := httptest.NewRecorder()
+
+		mockService.On("Delete", 1, 4, data.DeleteReject).Return(nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return precondition failed for an ETag of another tag", func(t *testing.T) {
+		for _, header := range []string{`"2-4"`, `"4"`} {
+			mockService, controller, router := setupTest()
+			router.PUT("/tags/:tagId", controller.Update)
+
+			req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(`{"name": "Updated Tag"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", header)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code, header)
+			mockService.AssertExpectations(t)
+		}
+	})
+
+	t.Run("should return precondition failed for a malformed If-Match list", func(t *testing.T) {
+		for _, header := range []string{`"1-4", 5`, `*, "1-4"`, `"1-4`} {
+			mockService, controller, router := setupTest()
+			router.PUT("/tags/:tagId", controller.Update)
+
+			req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(`{"name": "Updated Tag"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("If-Match", header)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code, header)
+			mockService.AssertExpectations(t)
+		}
+	})
+
+	t.Run("should return conflict when tag name exists", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/tags/:tagId", controller.Update)
+
+		requestBody := `{"name": "Golang"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Update", 1, mock.Anything, 0).Return(helper.ErrConflict)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Conflict"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should handle invalid JSON", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/tags/:tagId", controller.Update)
+
+		requestBody := `{"name":`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Internal Server Error"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return internal server error when service fails", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/tags/:tagId", controller.Update)
+
+		requestBody := `{"name": "Updated Tag"}`
+		req, _ := http.NewRequest("PUT", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Update", 1, mock.Anything, 0).Return(errors.New("unexpected error"))
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Internal Server Error"`)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestPatchTag(t *testing.T) {
+	t.Run("should apply a merge patch", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		requestBody := `{"name": "Patched Tag"}`
+		req, _ := http.NewRequest("PATCH", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Patch", 1, data.MergePatchContentType, []byte(requestBody), 0).Return(nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Successfully updated"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should apply a JSON patch with If-Match", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		requestBody := `[{"op": "replace", "path": "/name", "value": "Patched Tag"}]`
+		req, _ := http.NewRequest("PATCH", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json-patch+json; charset=utf-8")
+		req.Header.Set("If-Match", `"1-2"`)
+		w := httptest.NewRecorder()
+
+		mockService.On("Patch", 1, data.JSONPatchContentType, []byte(requestBody), 2).Return(nil)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject other content types", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		req, _ := http.NewRequest("PATCH", "/tags/1", bytes.NewBufferString(`{"name": "Patched Tag"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return unprocessable entity when patch cannot be applied", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		requestBody := `[{"op": "remove", "path": "/missing"}]`
+		req, _ := http.NewRequest("PATCH", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json-patch+json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Patch", 1, data.JSONPatchContentType, []byte(requestBody), 0).Return(helper.ErrInvalidPatch)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Unprocessable Entity"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request when patched tag is invalid", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		requestBody := `{"name": "ab"}`
+		req, _ := http.NewRequest("PATCH", "/tags/1", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Patch", 1, data.MergePatchContentType, []byte(requestBody), 0).Return(helper.ErrFailedValidation)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when tag doesn't exist", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PATCH("/tags/:tagId", controller.Patch)
+
+		requestBody := `{"name": "Patched Tag"}`
+		req, _ := http.NewRequest("PATCH", "/tags/999", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		w := httptest.NewRecorder()
+
+		mockService.On("Patch", 999, data.MergePatchContentType, []byte(requestBody), 0).Return(helper.ErrNotFound)
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	t.Run("should delete tag successfully", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 1, 0, data.DeleteReject).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Successfully deleted"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when tag doesn't exist", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 999, 0, data.DeleteReject).Return(helper.ErrNotFound)
+
+		req, _ := http.NewRequest("DELETE", "/tags/999", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should delete tag permanently when requested", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("DeletePermanently", 1, 0, data.DeleteReject).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/1?permanent=true", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return precondition failed when delete version is stale", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 1, 2, data.DeleteReject).Return(helper.ErrPreconditionFailed)
+
+		req, _ := http.NewRequest("DELETE", "/tags/1", nil)
+		req.Header.Set("If-Match", `"1-2"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid permanent flag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		req, _ := http.NewRequest("DELETE", "/tags/1?permanent=maybe", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should handle invalid ID format", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		req, _ := http.NewRequest("DELETE", "/tags/invalid-id", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Bad Request"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return internal server error when service fails", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 1, 0, data.DeleteReject).Return(errors.New("unexpected error"))
+
 		req, _ := http.NewRequest("DELETE", "/tags/1", nil)
 		w := httptest.NewRecorder()
 
@@ -365,3 +1020,642 @@ func TestDeleteTag(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestTrash(t *testing.T) {
+	t.Run("should list trashed tags alongside the id route", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/trash", controller.FindTrash)
+		router.GET("/tags/:tagId", controller.FindById)
+
+		trashed := []data.TagResponse{{Id: 1, Name: "Tag1", DeletedAt: "2024-05-01T02:00:00Z"}}
+		mockService.On("FindTrash", data.TagQuery{}).Return(trashed, data.NewPageMeta(1, 20, 1), nil)
+
+		req, _ := http.NewRequest("GET", "/tags/trash", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"deleted_at":"2024-05-01T02:00:00Z"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should restore a trashed tag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/restore", controller.Restore)
+
+		mockService.On("Restore", 1).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/tags/1/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when tag is not in trash", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/restore", controller.Restore)
+
+		mockService.On("Restore", 2).Return(helper.ErrNotFound)
+
+		req, _ := http.NewRequest("POST", "/tags/2/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return conflict when restored name is taken", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/restore", controller.Restore)
+
+		mockService.On("Restore", 3).Return(helper.ErrConflict)
+
+		req, _ := http.NewRequest("POST", "/tags/3/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should purge old trash", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/trash", controller.PurgeTrash)
+
+		mockService.On("PurgeTrash").Return(data.PurgeResponse{Purged: 2}, nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/trash", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"purged":2`)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestBulkTags(t *testing.T) {
+	t.Run("should create tags in bulk", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/bulk", controller.BulkCreate)
+
+		results := []data.BulkResult{{Index: 0, Id: 7, Success: true}, {Index: 1, Id: 8, Success: true}}
+		mockService.On("BulkCreate", []data.TagRequest{{Name: "first"}, {Name: "second"}}, false).Return(results, nil)
+
+		requestBody := `[{"name": "first"}, {"name": "second"}]`
+		req, _ := http.NewRequest("POST", "/tags/bulk", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `{"index":1,"id":8,"success":true}`)
+		assert.Contains(t, w.Body.String(), `"meta":{"total":2,"succeeded":2,"failed":0}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should report a rolled back batch with the status of its cause", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/bulk", controller.BulkCreate)
+
+		results := []data.BulkResult{{Index: 0, Error: "rolled back: resource already exists"}}
+		mockService.On("BulkCreate", []data.TagRequest{{Name: "taken"}}, false).Return(results, helper.ErrConflict)
+
+		req, _ := http.NewRequest("POST", "/tags/bulk", bytes.NewBufferString(`[{"name": "taken"}]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Conflict"`)
+		assert.Contains(t, w.Body.String(), `"failed":1`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should update tags in partial mode", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/tags/bulk", controller.BulkUpdate)
+		router.PUT("/tags/:tagId", controller.Update)
+
+		results := []data.BulkResult{{Index: 0, Id: 1, Success: true}, {Index: 1, Id: 9, Error: "resource not found"}}
+		requests := []data.BulkTagUpdateRequest{{Id: 1, Version: 2, TagRequest: data.TagRequest{Name: "first"}}, {Id: 9, TagRequest: data.TagRequest{Name: "missing"}}}
+		mockService.On("BulkUpdate", requests, true).Return(results, nil)
+
+		requestBody := `[{"id": 1, "name": "first", "version": 2}, {"id": 9, "name": "missing"}]`
+		req, _ := http.NewRequest("PUT", "/tags/bulk?partial=true", bytes.NewBufferString(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMultiStatus, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"Multi-Status"`)
+		assert.Contains(t, w.Body.String(), `"meta":{"total":2,"succeeded":1,"failed":1}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return unprocessable entity when no item of a partial batch succeeds", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/bulk", controller.BulkDelete)
+
+		results := []data.BulkResult{{Index: 0, Id: 8, Error: "resource not found"}, {Index: 1, Id: 9, Error: "resource not found"}}
+		mockService.On("BulkDelete", []int{8, 9}, true).Return(results, nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/bulk?partial=true", bytes.NewBufferString(`[8, 9]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), `"meta":{"total":2,"succeeded":0,"failed":2}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should delete tags in bulk", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/bulk", controller.BulkDelete)
+
+		results := []data.BulkResult{{Index: 0, Id: 1, Success: true}}
+		mockService.On("BulkDelete", []int{1}, false).Return(results, nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/bulk", bytes.NewBufferString(`[1]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for a rejected batch size", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/bulk", controller.BulkDelete)
+
+		mockService.On("BulkDelete", []int{}, false).Return(nil, helper.ErrFailedValidation)
+
+		req, _ := http.NewRequest("DELETE", "/tags/bulk", bytes.NewBufferString(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for a body that is not an array", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/bulk", controller.BulkCreate)
+
+		req, _ := http.NewRequest("POST", "/tags/bulk", bytes.NewBufferString(`{"name": "first"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestHierarchy(t *testing.T) {
+	golang := 2
+
+	t.Run("should list children of a tag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId/children", controller.FindChildren)
+
+		children := []data.TagResponse{{Id: 4, Name: "gin", ParentId: &golang}}
+		mockService.On("FindChildren", 2).Return(children, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/2/children", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `{"id":4,"name":"gin","parent_id":2,"version":0}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for ancestors of a missing tag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId/ancestors", controller.FindAncestors)
+
+		mockService.On("FindAncestors", 9).Return(nil, helper.ErrNotFound)
+
+		req, _ := http.NewRequest("GET", "/tags/9/ancestors", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return the tree as nested json", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/tree", controller.FindTree)
+		router.GET("/tags/:tagId", controller.FindById)
+
+		tree := []data.TagTreeNode{{
+			TagResponse: data.TagResponse{Id: 1, Name: "lang", Version: 1},
+			Children: []data.TagTreeNode{{
+				TagResponse: data.TagResponse{Id: 2, Name: "golang", ParentId: &golang, Version: 1},
+				Children:    []data.TagTreeNode{},
+			}},
+		}}
+		meta := data.TagTreeMeta{MaxDepth: 2, Nodes: 2, Truncated: true}
+		mockService.On("FindTree", data.TagTreeQuery{MaxDepth: 2}).Return(tree, meta, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/tree?max_depth=2", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"data":[{"id":1,"name":"lang","version":1,"children":[{"id":2,"name":"golang","parent_id":2,"version":1,"children":[]}]}]`)
+		assert.Contains(t, w.Body.String(), `{"max_depth":2,"nodes":2,"truncated":true}`)
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should reject an invalid max depth", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/tree", controller.FindTree)
+
+		mockService.On("FindTree", data.TagTreeQuery{MaxDepth: 99}).
+			Return(nil, data.TagTreeMeta{}, helper.ErrFailedValidationWrap(errors.New("max_depth"))).Once()
+
+		for _, query := range []string{"max_depth=deep", "max_depth=99"} {
+			req, _ := http.NewRequest("GET", "/tags/tree?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should move a tag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/move", controller.Move)
+
+		parent := 3
+		mockService.On("Move", 4, &parent, 2).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/tags/4/move", bytes.NewBufferString(`{"parent_id": 3}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"4-2"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for a move that creates a cycle", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/move", controller.Move)
+
+		parent := 4
+		mockService.On("Move", 1, &parent, 0).Return(helper.ErrFailedValidationWrap(errors.New("cycle")))
+
+		req, _ := http.NewRequest("POST", "/tags/1/move", bytes.NewBufferString(`{"parent_id": 4}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return conflict when deleting a tag with children", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 2, 0, data.DeleteReject).Return(helper.ErrHasChildren)
+
+		req, _ := http.NewRequest("DELETE", "/tags/2", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "strategy=cascade")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should pass the delete strategy to the service", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 2, 0, data.DeleteReparent).Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/2?strategy=reparent", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an unknown strategy", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId", controller.Delete)
+
+		mockService.On("Delete", 2, 0, "orphan").Return(helper.ErrFailedValidationWrap(errors.New("unknown delete strategy")))
+
+		req, _ := http.NewRequest("DELETE", "/tags/2?strategy=orphan", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestAliases(t *testing.T) {
+	t.Run("should find a tag by alias", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/name/:name", controller.FindByName)
+
+		tag := data.TagResponse{Id: 3, Name: "JavaScript", Aliases: []string{"js"}, Version: 2}
+		mockService.On("FindByName", "js").Return(tag, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/name/js", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3-2"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Body.String(), `"aliases":["js"]`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should not confirm a name that now resolves to another tag", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/name/:name", controller.FindByName)
+
+		tag := data.TagResponse{Id: 5, Name: "JavaScript", Version: 2}
+		mockService.On("FindByName", "js").Return(tag, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/name/js", nil)
+		req.Header.Set("If-None-Match", `"3-2"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"5-2"`, w.Header().Get("ETag"))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for an unknown name", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/name/:name", controller.FindByName)
+
+		mockService.On("FindByName", "nope").Return(data.TagResponse{}, helper.ErrNotFound)
+
+		req, _ := http.NewRequest("GET", "/tags/name/nope", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should add an alias", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/aliases", controller.AddAlias)
+
+		mockService.On("AddAlias", 3, data.AliasRequest{Name: "js"}).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/tags/3/aliases", bytes.NewBufferString(`{"name": "js"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return conflict for a taken alias", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/aliases", controller.AddAlias)
+
+		mockService.On("AddAlias", 3, data.AliasRequest{Name: "golang"}).Return(helper.ErrConflict)
+
+		req, _ := http.NewRequest("POST", "/tags/3/aliases", bytes.NewBufferString(`{"name": "golang"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should delete an alias", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/tags/:tagId/aliases/:alias", controller.DeleteAlias)
+
+		mockService.On("DeleteAlias", 3, "js").Return(nil)
+
+		req, _ := http.NewRequest("DELETE", "/tags/3/aliases/js", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMergeTags(t *testing.T) {
+	t.Run("should merge tags", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/merge", controller.Merge)
+
+		mockService.On("Merge", 3, data.MergeTagRequest{SourceIds: []int{5, 6}}, 4).Return(nil)
+
+		req, _ := http.NewRequest("POST", "/tags/3/merge", bytes.NewBufferString(`{"source_ids": [5, 6]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3-4"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when a source is missing", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/merge", controller.Merge)
+
+		mockService.On("Merge", 3, data.MergeTagRequest{SourceIds: []int{9}}, 0).Return(helper.ErrNotFound)
+
+		req, _ := http.NewRequest("POST", "/tags/3/merge", bytes.NewBufferString(`{"source_ids": [9]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an invalid body", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags/:tagId/merge", controller.Merge)
+
+		req, _ := http.NewRequest("POST", "/tags/3/merge", bytes.NewBufferString(`{"source_ids": "5"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestTaggings(t *testing.T) {
+	article := data.ResourceRef{ResourceType: "article", ResourceId: "a-42"}
+
+	t.Run("should attach a tag to a resource", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/resources/:resourceType/:resourceId/tags/:tagId", controller.Attach)
+
+		mockService.On("Attach", 1, article).Return(nil)
+
+		req, _ := http.NewRequest("PUT", "/resources/article/a-42/tags/1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found when detaching a missing tagging", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.DELETE("/resources/:resourceType/:resourceId/tags/:tagId", controller.Detach)
+
+		mockService.On("Detach", 1, article).Return(helper.ErrNotFound)
+
+		req, _ := http.NewRequest("DELETE", "/resources/article/a-42/tags/1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should list tags of a resource", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/resources/:resourceType/:resourceId/tags", controller.FindByResource)
+
+		mockService.On("FindByResource", article).Return([]data.TagResponse{{Id: 1, Name: "golang", Version: 1}}, nil)
+
+		req, _ := http.NewRequest("GET", "/resources/article/a-42/tags", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"data":[{"id":1,"name":"golang","version":1}]`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should list resources of a tag with paging", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags/:tagId/resources", controller.FindResources)
+
+		resources := []data.ResourceResponse{{ResourceType: "article", ResourceId: "a-42"}}
+		meta := data.PageMeta{Page: 1, PageSize: 10, Total: 1, TotalPages: 1}
+		mockService.On("FindResources", 1, data.TaggingQuery{PageSize: 10, ResourceType: "article"}).Return(resources, meta, nil)
+
+		req, _ := http.NewRequest("GET", "/tags/1/resources?page_size=10&resource_type=article", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"meta":{"page":1,"page_size":10,"total":1,"total_pages":1}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an invalid tag id", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.PUT("/resources/:resourceType/:resourceId/tags/:tagId", controller.Attach)
+
+		req, _ := http.NewRequest("PUT", "/resources/article/a-42/tags/golang", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestTagMetadata(t *testing.T) {
+	t.Run("should pass attribute filters to the service", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.GET("/tags", controller.FindAll)
+
+		query := data.TagQuery{Attributes: map[string][]string{"team": {"platform", "web"}, "public": {"true"}}}
+		expectedTags := []data.TagResponse{{Id: 1, Name: "platform", Color: "#336699", Attributes: map[string]any{"team": "platform"}}}
+		mockService.On("FindAll", query).Return(expectedTags, data.NewPageMeta(1, 20, 1), nil)
+
+		req, _ := http.NewRequest("GET", "/tags?attr.team=platform&attr.team=web&attr.public=true", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"color":"#336699"`)
+		assert.Contains(t, w.Body.String(), `"attributes":{"team":"platform"}`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should accept metadata on create", func(t *testing.T) {
+		mockService, controller, router := setupTest()
+		router.POST("/tags", controller.Create)
+
+		request := data.TagRequest{Name: "platform", Description: "Shared services", Color: "#336699", Icon: "server",
+			Attributes: map[string]any{"team": "platform", "tier": float64(1)}}
+		mockService.On("Create", request).Return(nil)
+
+		body := `{"name":"platform","description":"Shared services","color":"#336699","icon":"server","attributes":{"team":"platform","tier":1}}`
+		req, _ := http.NewRequest("POST", "/tags", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"errors"
+	"go-gin-project/api/service"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/responsejson"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TodoController struct {
+	todoService service.TodoService
+}
+
+func NewTodoController(service service.TodoService) *TodoController {
+	return &TodoController{
+		todoService: service,
+	}
+}
+
+func (controller *TodoController) Create(ctx *gin.Context) {
+	createTodoRequest := data.TodoRequest{}
+	if err := ctx.ShouldBindJSON(&createTodoRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	todoResponse, err := controller.todoService.Create(ctx.Request.Context(), createTodoRequest)
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.Success(ctx, "create", todoResponse)
+}
+
+func (controller *TodoController) FindAll(ctx *gin.Context) {
+	query := data.TodoQuery{}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	todoResponse, meta, err := controller.todoService.FindAll(ctx.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.ConditionalSuccessWithMeta(ctx, todoResponse, meta)
+}
+
+func (controller *TodoController) FindById(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("todoId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	todoResponse, err := controller.todoService.FindById(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Todo not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+	responsejson.Success(ctx, "read", todoResponse)
+}
+
+func (controller *TodoController) Update(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("todoId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+	updateTodoRequest := data.TodoRequest{}
+	if err := ctx.ShouldBindJSON(&updateTodoRequest); err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	err = controller.todoService.Update(ctx.Request.Context(), id, updateTodoRequest)
+	if err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Todo not found")
+			return
+		}
+		if errors.Is(err, helper.ErrFailedValidation) {
+			responsejson.BadRequest(ctx, err)
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "update", nil)
+}
+
+func (controller *TodoController) Delete(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("todoId"))
+	if err != nil {
+		responsejson.BadRequest(ctx, err)
+		return
+	}
+
+	if err := controller.todoService.Delete(ctx.Request.Context(), id); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			responsejson.NotFound(ctx, "Todo not found")
+			return
+		}
+		responsejson.InternalServerError(ctx, err)
+		return
+	}
+
+	responsejson.Success(ctx, "delete", nil)
+}
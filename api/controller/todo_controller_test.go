@@ -0,0 +1,143 @@
+package controller_test
+
+import (
+	"bytes"
+	"context"
+	"go-gin-project/api/controller"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTodoService struct {
+	mock.Mock
+}
+
+func (m *MockTodoService) Create(_ context.Context, request data.TodoRequest) (data.TodoResponse, error) {
+	args := m.Called(request)
+	return args.Get(0).(data.TodoResponse), args.Error(1)
+}
+
+func (m *MockTodoService) FindAll(_ context.Context, query data.TodoQuery) ([]data.TodoResponse, data.PageMeta, error) {
+	args := m.Called(query)
+	return args.Get(0).([]data.TodoResponse), args.Get(1).(data.PageMeta), args.Error(2)
+}
+
+func (m *MockTodoService) FindById(_ context.Context, todoId int) (data.TodoResponse, error) {
+	args := m.Called(todoId)
+	return args.Get(0).(data.TodoResponse), args.Error(1)
+}
+
+func (m *MockTodoService) Update(_ context.Context, todoId int, request data.TodoRequest) error {
+	args := m.Called(todoId, request)
+	return args.Error(0)
+}
+
+func (m *MockTodoService) Delete(_ context.Context, todoId int) error {
+	args := m.Called(todoId)
+	return args.Error(0)
+}
+
+func setupTodoTest() (*MockTodoService, *controller.TodoController, *gin.Engine) {
+	mockService := new(MockTodoService)
+	controller := controller.NewTodoController(mockService)
+	router := setupRouter()
+	return mockService, controller, router
+}
+
+func TestTodoController(t *testing.T) {
+	t.Run("should create a todo and return it", func(t *testing.T) {
+		mockService, controller, router := setupTodoTest()
+		router.POST("/todo", controller.Create)
+
+		request := data.TodoRequest{Title: "Write docs", TagIds: []int{1, 2}}
+		response := data.TodoResponse{Id: 1, Title: "Write docs", Status: data.TodoOpen,
+			Tags: []data.TagResponse{{Id: 1, Name: "docs"}, {Id: 2, Name: "go"}}}
+		mockService.On("Create", request).Return(response, nil)
+
+		req, _ := http.NewRequest("POST", "/todo", bytes.NewBufferString(`{"title":"Write docs","tag_ids":[1,2]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"title":"Write docs"`)
+		assert.Contains(t, w.Body.String(), `"name":"go"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for unknown tags", func(t *testing.T) {
+		mockService, controller, router := setupTodoTest()
+		router.POST("/todo", controller.Create)
+
+		mockService.On("Create", mock.Anything).Return(data.TodoResponse{}, helper.ErrFailedValidation)
+
+		req, _ := http.NewRequest("POST", "/todo", bytes.NewBufferString(`{"title":"Write docs","tag_ids":[9]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("should filter todos by all of the given tags", func(t *testing.T) {
+		mockService, controller, router := setupTodoTest()
+		router.GET("/todo", controller.FindAll)
+
+		query := data.TodoQuery{TagIds: []int{1, 2}, TagMatch: "all"}
+		mockService.On("FindAll", query).Return([]data.TodoResponse{{Id: 1, Title: "Write docs"}}, data.NewPageMeta(1, 20, 1), nil)
+
+		req, _ := http.NewRequest("GET", "/todo?tag_id=1&tag_id=2&tag_match=all", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":1`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for a missing todo", func(t *testing.T) {
+		mockService, controller, router := setupTodoTest()
+		router.GET("/todo/:todoId", controller.FindById)
+		router.PUT("/todo/:todoId", controller.Update)
+		router.DELETE("/todo/:todoId", controller.Delete)
+
+		mockService.On("FindById", 9).Return(data.TodoResponse{}, helper.ErrNotFound)
+		mockService.On("Update", 9, mock.Anything).Return(helper.ErrNotFound)
+		mockService.On("Delete", 9).Return(helper.ErrNotFound)
+
+		for _, method := range []string{"GET", "PUT", "DELETE"} {
+			req, _ := http.NewRequest(method, "/todo/9", bytes.NewBufferString(`{"title":"Write docs"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code, method)
+			assert.Contains(t, w.Body.String(), "Todo not found")
+		}
+	})
+
+	t.Run("should reject a non numeric id", func(t *testing.T) {
+		mockService, controller, router := setupTodoTest()
+		router.GET("/todo/:todoId", controller.FindById)
+		router.PUT("/todo/:todoId", controller.Update)
+		router.DELETE("/todo/:todoId", controller.Delete)
+
+		for _, method := range []string{"GET", "PUT", "DELETE"} {
+			req, _ := http.NewRequest(method, "/todo/1%20OR%201=1", bytes.NewBufferString(`{"title":"Write docs"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, method)
+		}
+		mockService.AssertNotCalled(t, "FindById", mock.Anything)
+		mockService.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
@@ -8,17 +8,61 @@ import (
 	"go-gin-project/api/repository"
 	"go-gin-project/api/service"
 	"go-gin-project/config"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/middleware"
+	"go-gin-project/router"
+	"net/http"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
-func InitializeTagsController() *controller.TagsController {
+var tagsSet = wire.NewSet(
+	controller.NewTagsController,
+	service.NewTracedTagsService,
+	repository.NewInstrumentedTagsRepository,
+	config.NewCursorSigner,
+	config.NewTrashRetention,
+)
+
+var todoSet = wire.NewSet(
+	controller.NewTodoController,
+	service.NewTodoServiceImpl,
+	repository.NewTodoRepositoryImpl,
+)
+
+var apiKeysSet = wire.NewSet(
+	controller.NewApiKeysController,
+	service.NewApiKeysServiceImpl,
+	repository.NewApiKeysRepositoryImpl,
+	wire.Bind(new(middleware.ApiKeyAuthenticator), new(service.ApiKeysService)),
+)
+
+var healthSet = wire.NewSet(
+	controller.NewHealthController,
+	config.NewHealthRegistry,
+)
+
+func InitializeApp(cfg *config.Config) (*App, error) {
 	wire.Build(
-		controller.NewTagsController,
-		service.NewTagsServiceImpl,
-		repository.NewTagsRepositoryImpl,
-		config.DatabaseConnection,
+		wire.Struct(new(App), "*"),
+		wire.FieldsOf(new(*config.Config), "TrustedProxies", "Log", "Database", "Auth", "Tags", "RateLimit", "Tracing", "Health"),
+		config.NewServer,
+		wire.Bind(new(http.Handler), new(*gin.Engine)),
+		router.NewRouter,
+		wire.Struct(new(router.Controllers), "*"),
+		wire.Struct(new(router.RateLimits), "*"),
+		tagsSet,
+		todoSet,
+		apiKeysSet,
+		healthSet,
+		config.NewLogger,
+		config.NewDatabase,
+		config.NewTracerProvider,
+		config.NewTokenVerifier,
+		config.NewRateLimitStore,
 		config.NewValidator,
+		metrics.New,
 	)
-	return &controller.TagsController{}
+	return &App{}, nil
 }
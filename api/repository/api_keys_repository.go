@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ApiKeysRepository interface {
+	Save(ctx context.Context, key model.ApiKey) (model.ApiKey, error)
+	FindAll(ctx context.Context, tenantId *string) ([]model.ApiKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (model.ApiKey, error)
+	Revoke(ctx context.Context, keyId int, tenantId *string, at time.Time) error
+	MarkUsed(ctx context.Context, keyId int, at time.Time) error
+}
+
+// NewApiKeysRepositoryImpl returns a repository over every API key. Keys are not scoped by the tenant of the
+// context: FindAll and Revoke take the tenant to limit them to, and nil for keys of every tenant.
+func NewApiKeysRepositoryImpl(Db *gorm.DB) ApiKeysRepository {
+	return &ApiKeysRepositoryImpl{Db: Db}
+}
+
+type ApiKeysRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func (a *ApiKeysRepositoryImpl) Save(ctx context.Context, key model.ApiKey) (model.ApiKey, error) {
+	db := a.Db.WithContext(ctx)
+	if err := db.Create(&key).Error; err != nil {
+		return model.ApiKey{}, translateError(db, err)
+	}
+	return key, nil
+}
+
+func (a *ApiKeysRepositoryImpl) FindAll(ctx context.Context, tenantId *string) ([]model.ApiKey, error) {
+	var keys []model.ApiKey
+	if err := a.ofTenant(ctx, tenantId).Order("id ASC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (a *ApiKeysRepositoryImpl) FindByPrefix(ctx context.Context, prefix string) (model.ApiKey, error) {
+	var key model.ApiKey
+	result := a.Db.WithContext(ctx).Where("prefix = ?", prefix).First(&key)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return model.ApiKey{}, helper.ErrNotFound
+	} else if result.Error != nil {
+		return model.ApiKey{}, result.Error
+	}
+	return key, nil
+}
+
+// Revoke stamps the key as revoked. Revoking a revoked key keeps the first revocation time.
+func (a *ApiKeysRepositoryImpl) Revoke(ctx context.Context, keyId int, tenantId *string, at time.Time) error {
+	db := a.Db.WithContext(ctx)
+	var key model.ApiKey
+	result := a.ofTenant(ctx, tenantId).Select("id", "revoked_at").Where("id = ?", keyId).First(&key)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return helper.ErrNotFound
+	} else if result.Error != nil {
+		return result.Error
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	return db.Model(&model.ApiKey{}).Where("id = ? AND revoked_at IS NULL", keyId).Update("revoked_at", at).Error
+}
+
+func (a *ApiKeysRepositoryImpl) MarkUsed(ctx context.Context, keyId int, at time.Time) error {
+	return a.Db.WithContext(ctx).Model(&model.ApiKey{}).Where("id = ?", keyId).Update("last_used_at", at).Error
+}
+
+// ofTenant limits a query to the keys of tenantId, or not at all when it is nil.
+func (a *ApiKeysRepositoryImpl) ofTenant(ctx context.Context, tenantId *string) *gorm.DB {
+	db := a.Db.WithContext(ctx)
+	if tenantId != nil {
+		db = db.Where("tenant_id = ?", *tenantId)
+	}
+	return db
+}
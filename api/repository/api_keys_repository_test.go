@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"go-gin-project/api/repository"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeysRepository(t *testing.T) {
+	// Keys are not scoped by the tenant of the context, so none is needed there.
+	ctx := context.Background()
+
+	t.Run("should save and find a key by prefix", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+
+		saved, err := repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash",
+			Scopes: model.ApiKeyScopes{"tags:read"}, TenantId: "team-a"})
+		assert.Nil(t, err)
+		assert.NotZero(t, saved.Id)
+
+		key, err := repo.FindByPrefix(ctx, "tk_00000001")
+		assert.Nil(t, err)
+		assert.Equal(t, "ci", key.Name)
+		assert.Equal(t, model.ApiKeyScopes{"tags:read"}, key.Scopes)
+		assert.Equal(t, "team-a", key.TenantId)
+
+		_, err = repo.FindByPrefix(ctx, "tk_00000002")
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should reject a duplicate prefix", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash"})
+
+		_, err := repo.Save(ctx, model.ApiKey{Name: "other", Prefix: "tk_00000001", SecretHash: "hash"})
+		assert.ErrorIs(t, err, helper.ErrConflict)
+	})
+
+	t.Run("should list keys in creation order", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash"})
+		repo.Save(ctx, model.ApiKey{Name: "billing", Prefix: "tk_00000002", SecretHash: "hash"})
+
+		keys, err := repo.FindAll(ctx, nil)
+		assert.Nil(t, err)
+		assert.Len(t, keys, 2)
+		assert.Equal(t, "ci", keys[0].Name)
+		assert.Equal(t, "billing", keys[1].Name)
+	})
+
+	t.Run("should list only the keys of a tenant", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash", TenantId: "team-a"})
+		repo.Save(ctx, model.ApiKey{Name: "billing", Prefix: "tk_00000002", SecretHash: "hash", TenantId: "team-b"})
+		repo.Save(ctx, model.ApiKey{Name: "ops", Prefix: "tk_00000003", SecretHash: "hash"})
+
+		teamA, none := "team-a", ""
+		keys, err := repo.FindAll(ctx, &teamA)
+		assert.Nil(t, err)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, "ci", keys[0].Name)
+
+		keys, err = repo.FindAll(ctx, &none)
+		assert.Nil(t, err)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, "ops", keys[0].Name)
+	})
+
+	t.Run("should not revoke a key of another tenant", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		saved, _ := repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash", TenantId: "team-a"})
+		teamA, teamB := "team-a", "team-b"
+		at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		assert.ErrorIs(t, repo.Revoke(ctx, saved.Id, &teamB, at), helper.ErrNotFound)
+		key, _ := repo.FindByPrefix(ctx, "tk_00000001")
+		assert.Nil(t, key.RevokedAt)
+
+		assert.Nil(t, repo.Revoke(ctx, saved.Id, &teamA, at))
+		key, _ = repo.FindByPrefix(ctx, "tk_00000001")
+		assert.NotNil(t, key.RevokedAt)
+	})
+
+	t.Run("should keep the first revocation time", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		saved, _ := repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash"})
+		first := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		assert.Nil(t, repo.Revoke(ctx, saved.Id, nil, first))
+		assert.Nil(t, repo.Revoke(ctx, saved.Id, nil, first.Add(time.Hour)))
+
+		key, _ := repo.FindByPrefix(ctx, "tk_00000001")
+		assert.True(t, first.Equal(*key.RevokedAt))
+		assert.ErrorIs(t, repo.Revoke(ctx, 99, nil, first), helper.ErrNotFound)
+	})
+
+	t.Run("should record the last use", func(t *testing.T) {
+		repo := repository.NewApiKeysRepositoryImpl(setupTestDB())
+		saved, _ := repo.Save(ctx, model.ApiKey{Name: "ci", Prefix: "tk_00000001", SecretHash: "hash"})
+		used := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+		assert.Nil(t, repo.MarkUsed(ctx, saved.Id, used))
+
+		key, _ := repo.FindByPrefix(ctx, "tk_00000001")
+		assert.True(t, used.Equal(*key.LastUsedAt))
+	})
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"errors"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+
+	"gorm.io/gorm"
+)
+
+// translateError maps unique violations reported by the driver (Postgres 23505, SQLite 1555/2067)
+// and names taken by an alias to helper.ErrConflict and leaves every other error untouched.
+func translateError(db *gorm.DB, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, model.ErrAliasTaken) {
+		return helper.ErrConflict
+	}
+	translated := err
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		translated = translator.Translate(err)
+	}
+	if errors.Is(translated, gorm.ErrDuplicatedKey) {
+		return helper.ErrConflict
+	}
+	return err
+}
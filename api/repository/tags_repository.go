@@ -1,21 +1,53 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"go-gin-project/data"
 	"go-gin-project/helper"
+	"go-gin-project/helper/logging"
 	"go-gin-project/model"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TagsRepository interface {
-	Save(tag model.Tags) error
-	FindAll() ([]model.Tags, error)
-	FindById(tagId string) (tag model.Tags, err error)
-	Update(tag model.Tags) error
-	Delete(tagId int) error
+	Save(ctx context.Context, tag model.Tags) error
+	FindAll(ctx context.Context, query data.TagQuery) ([]model.Tags, int64, error)
+	FindAllByCursor(ctx context.Context, query data.TagQuery, cursor *data.TagCursor) ([]model.Tags, bool, error)
+	FindById(ctx context.Context, tagId int) (tag model.Tags, err error)
+	FindByName(ctx context.Context, name string) (model.Tags, error)
+	Update(ctx context.Context, tag model.Tags) error
+	FindChildren(ctx context.Context, tagId int) ([]model.Tags, error)
+	FindAncestors(ctx context.Context, tagId int) ([]model.Tags, error)
+	FindTree(ctx context.Context, maxDepth int, limit int) (tags []model.Tags, truncated bool, err error)
+	Delete(ctx context.Context, tagId int, version int, strategy string) error
+	DeletePermanently(ctx context.Context, tagId int, version int, strategy string) error
+	FindTrash(ctx context.Context, query data.TagQuery) ([]model.Tags, int64, error)
+	Restore(ctx context.Context, tagId int) error
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error)
+	SaveBatch(ctx context.Context, tags []model.Tags) ([]model.Tags, error)
+	UpdateBatch(ctx context.Context, tags []model.Tags) error
+	DeleteBatch(ctx context.Context, tagIds []int) error
+	SaveAlias(ctx context.Context, alias model.TagAlias) error
+	DeleteAlias(ctx context.Context, tagId int, name string) error
+	Merge(ctx context.Context, targetId int, sourceIds []int, version int) error
+	Attach(ctx context.Context, tagging model.Tagging) error
+	Detach(ctx context.Context, tagging model.Tagging) error
+	FindByResource(ctx context.Context, resourceType string, resourceId string) ([]model.Tags, error)
+	FindResources(ctx context.Context, tagId int, query data.TaggingQuery) ([]model.Tagging, int64, error)
 }
 
+const batchSize = 100
+
+// maxTagDepth bounds the recursive hierarchy queries in case a cycle ever reaches the table.
+const maxTagDepth = 100
+
 func NewTagsRepositoryImpl(Db *gorm.DB) TagsRepository {
 	return &TagsRepositoryImpl{Db: Db}
 }
@@ -24,26 +56,136 @@ type TagsRepositoryImpl struct {
 	Db *gorm.DB
 }
 
-func (t *TagsRepositoryImpl) Save(tag model.Tags) error {
-	result := t.Db.Create(&tag)
+func (t *TagsRepositoryImpl) scoped(ctx context.Context) *gorm.DB {
+	return scoped(t.Db, ctx)
+}
+
+func (t *TagsRepositoryImpl) Save(ctx context.Context, tag model.Tags) error {
+	result := t.scoped(ctx).Create(&tag)
 	if result.Error != nil {
-		return result.Error
+		return translateError(t.Db, result.Error)
 	}
 	return nil
 }
 
-func (t *TagsRepositoryImpl) FindAll() ([]model.Tags, error) {
+var tagSortColumns = map[string]string{
+	"id":    "id ASC",
+	"-id":   "id DESC",
+	"name":  "name ASC, id ASC",
+	"-name": "name DESC, id DESC",
+}
+
+func (t *TagsRepositoryImpl) FindAll(ctx context.Context, query data.TagQuery) ([]model.Tags, int64, error) {
+	var total int64
+	result := t.scoped(ctx).Model(&model.Tags{}).Scopes(tagFilters(query)).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	order, ok := tagSortColumns[query.Sort]
+	if !ok {
+		order = tagSortColumns["id"]
+	}
+
 	var tags []model.Tags
-	result := t.Db.Find(&tags)
+	result = t.scoped(ctx).Scopes(tagFilters(query)).
+		Order(order).
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&tags)
 	if result.Error != nil {
-		return nil, result.Error
+		return nil, 0, result.Error
 	}
-	return tags, nil
+	return tags, total, nil
 }
 
-func (t *TagsRepositoryImpl) FindById(tagId string) (tagModel model.Tags, err error) {
+// FindAllByCursor seeks past the cursor position instead of counting offsets, so pages stay stable
+// while rows are inserted. It returns at most query.PageSize rows in display order and reports
+// whether more rows exist in the direction of travel.
+func (t *TagsRepositoryImpl) FindAllByCursor(ctx context.Context, query data.TagQuery, cursor *data.TagCursor) ([]model.Tags, bool, error) {
+	byName := strings.TrimPrefix(query.Sort, "-") == "name"
+	descending := strings.HasPrefix(query.Sort, "-")
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		descending = !descending
+	}
+
+	direction, comparison := "ASC", ">"
+	if descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	db := t.scoped(ctx).Scopes(tagFilters(query))
+	if cursor != nil {
+		if byName {
+			db = db.Where("(name "+comparison+" ? OR (name = ? AND id "+comparison+" ?))", cursor.Name, cursor.Name, cursor.Id)
+		} else {
+			db = db.Where("id "+comparison+" ?", cursor.Id)
+		}
+	}
+	if byName {
+		db = db.Order("name " + direction)
+	}
+
+	var tags []model.Tags
+	result := db.Order("id " + direction).Limit(query.PageSize + 1).Find(&tags)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	hasMore := len(tags) > query.PageSize
+	if hasMore {
+		tags = tags[:query.PageSize]
+	}
+	if backward {
+		for i, j := 0, len(tags)-1; i < j; i, j = i+1, j-1 {
+			tags[i], tags[j] = tags[j], tags[i]
+		}
+	}
+	return tags, hasMore, nil
+}
+
+func tagFilters(query data.TagQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.NameContains != "" {
+			db = db.Where("LOWER(name) LIKE ? ESCAPE '\\'", "%"+escapeLike(strings.ToLower(query.NameContains))+"%")
+		}
+		if query.NamePrefix != "" {
+			db = db.Where("LOWER(name) LIKE ? ESCAPE '\\'", escapeLike(strings.ToLower(query.NamePrefix))+"%")
+		}
+		if !query.CreatedAfter.IsZero() {
+			db = db.Where("created_at > ?", query.CreatedAfter.UTC())
+		}
+		if !query.UpdatedSince.IsZero() {
+			db = db.Where("updated_at >= ?", query.UpdatedSince.UTC())
+		}
+		for _, key := range slices.Sorted(maps.Keys(query.Attributes)) {
+			db = attributeFilter(db, key, query.Attributes[key])
+		}
+		return db
+	}
+}
+
+// attributeFilter matches tags whose attribute key, read as text, is one of values.
+// Booleans read as "true" and "false" on every database.
+func attributeFilter(db *gorm.DB, key string, values []string) *gorm.DB {
+	if db.Dialector.Name() == "postgres" {
+		return db.Where("attributes ->> ? IN ?", key, values)
+	}
+	path := `$."` + key + `"`
+	return db.Where("CASE json_type(attributes, ?) WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' "+
+		"ELSE CAST(json_extract(attributes, ?) AS TEXT) END IN ?", path, path, values)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+func (t *TagsRepositoryImpl) FindById(ctx context.Context, tagId int) (tagModel model.Tags, err error) {
 	var tag model.Tags
-	result := t.Db.First(&tag, tagId)
+	result := t.scoped(ctx).Preload("Aliases").Where("id = ?", tagId).First(&tag)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return model.Tags{}, helper.ErrNotFound
 	} else if result.Error != nil {
@@ -53,21 +195,677 @@ func (t *TagsRepositoryImpl) FindById(tagId string) (tagModel model.Tags, err er
 	return tag, nil
 }
 
-func (t *TagsRepositoryImpl) Update(tags model.Tags) error {
-	result := t.Db.Model(&tags).Updates(tags)
+// FindByName looks a tag up by its name or one of its aliases, ignoring case.
+func (t *TagsRepositoryImpl) FindByName(ctx context.Context, name string) (model.Tags, error) {
+	normalizedName := model.NormalizeTagName(name)
+	aliased := t.scoped(ctx).Model(&model.TagAlias{}).Select("tag_id").Where("normalized_name = ?", normalizedName)
+
+	var tag model.Tags
+	result := t.scoped(ctx).Preload("Aliases").
+		Where("normalized_name = ? OR id IN (?)", normalizedName, aliased).
+		First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return model.Tags{}, helper.ErrNotFound
+	} else if result.Error != nil {
+		return model.Tags{}, result.Error
+	}
+
+	return tag, nil
+}
+
+// FindChildren returns the direct children of a tag ordered by name.
+func (t *TagsRepositoryImpl) FindChildren(ctx context.Context, tagId int) ([]model.Tags, error) {
+	var tags []model.Tags
+	result := t.scoped(ctx).Where("parent_id = ?", tagId).Order("name ASC, id ASC").Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindAncestors returns the chain of parents above a tag, starting at the root.
+func (t *TagsRepositoryImpl) FindAncestors(ctx context.Context, tagId int) ([]model.Tags, error) {
+	db := t.scoped(ctx)
+	tenantId := statementTenant(db)
+
+	var tags []model.Tags
+	result := db.Raw(`WITH RECURSIVE ancestors (id, parent_id, depth) AS (
+			SELECT id, parent_id, 0 FROM tags WHERE id = ? AND tenant_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT tags.id, tags.parent_id, ancestors.depth + 1 FROM tags
+			JOIN ancestors ON tags.id = ancestors.parent_id
+			WHERE tags.tenant_id = ? AND tags.deleted_at IS NULL AND ancestors.depth < ?
+		)
+		SELECT tags.* FROM tags JOIN ancestors ON tags.id = ancestors.id
+		WHERE ancestors.depth > 0
+		ORDER BY ancestors.depth DESC`, tagId, tenantId, tenantId, maxTagDepth).Scan(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindTree returns the active tags of the top maxDepth levels of the hierarchy, at most limit of them, for the
+// caller to nest by ParentId. Tags whose parent is in the trash are roots. Tags come level by level and by name
+// within a level, so the parent of every returned tag is returned too. truncated reports whether tags were left out.
+func (t *TagsRepositoryImpl) FindTree(ctx context.Context, maxDepth int, limit int) ([]model.Tags, bool, error) {
+	db := t.scoped(ctx)
+	tenantId := statementTenant(db)
+
+	// The walk goes one level past maxDepth, so tags left out below it show up as truncation.
+	var rows []struct {
+		model.Tags
+		Depth int
+	}
+	result := db.Raw(`WITH RECURSIVE tree (id, depth) AS (
+			SELECT roots.id, 1 FROM tags roots
+			WHERE roots.tenant_id = ? AND roots.deleted_at IS NULL AND NOT EXISTS (
+				SELECT 1 FROM tags parents
+				WHERE parents.id = roots.parent_id AND parents.tenant_id = ? AND parents.deleted_at IS NULL
+			)
+			UNION ALL
+			SELECT tags.id, tree.depth + 1 FROM tags
+			JOIN tree ON tags.parent_id = tree.id
+			WHERE tags.tenant_id = ? AND tags.deleted_at IS NULL AND tree.depth <= ?
+		)
+		SELECT tags.*, tree.depth FROM tags JOIN tree ON tags.id = tree.id
+		ORDER BY tree.depth ASC, tags.name ASC, tags.id ASC
+		LIMIT ?`, tenantId, tenantId, tenantId, maxDepth, limit+1).Scan(&rows)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	tags := []model.Tags{}
+	for _, row := range rows {
+		if row.Depth > maxDepth || len(tags) == limit {
+			return tags, true, nil
+		}
+		tags = append(tags, row.Tags)
+	}
+	return tags, false, nil
+}
+
+// descendantIds collects the ids of every tag of the session's tenant below tagId. Trashed tags are only
+// followed when unscoped is set.
+func descendantIds(db *gorm.DB, tagId int, unscoped bool) ([]int, error) {
+	active := " AND tags.deleted_at IS NULL"
+	if unscoped {
+		active = ""
+	}
+	tenantId := statementTenant(db)
+
+	var ids []int
+	result := db.Raw(`WITH RECURSIVE descendants (id, depth) AS (
+			SELECT id, 1 FROM tags WHERE parent_id = ? AND tenant_id = ?`+active+`
+			UNION ALL
+			SELECT tags.id, descendants.depth + 1 FROM tags
+			JOIN descendants ON tags.parent_id = descendants.id
+			WHERE tags.tenant_id = ? AND descendants.depth < ?`+active+`
+		)
+		SELECT DISTINCT id FROM descendants`, tagId, tenantId, tenantId, maxTagDepth).Scan(&ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+// Update writes the tag only if its stored version still equals tags.Version and bumps the version,
+// so two writers that read the same version cannot both succeed.
+func (t *TagsRepositoryImpl) Update(ctx context.Context, tags model.Tags) error {
+	updated := tags
+	updated.Version = tags.Version + 1
+	result := t.scoped(ctx).Model(&tags).
+		Where("version = ?", tags.Version).
+		Select("Name", "NormalizedName", "ParentId", "Description", "Color", "Icon", "Attributes", "Version").
+		Updates(updated)
+	if result.Error != nil {
+		return translateError(t.Db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return helper.ErrPreconditionFailed
+	}
+	return nil
+}
+
+// Delete moves the tag to the trash. A non-zero version makes the delete conditional on it.
+// strategy decides what happens to the tag's children, see data.DeleteReject and friends. Descendants trashed
+// on cascade get the tag's deleted_at, which is how Restore finds them again.
+// Taggings of trashed tags are kept, hidden from listings, so a restore brings them back.
+func (t *TagsRepositoryImpl) Delete(ctx context.Context, tagsId int, version int, strategy string) error {
+	return t.delete(ctx, tagsId, version, strategy, false)
+}
+
+// DeletePermanently removes the tag, and its descendants on cascade, together with their taggings, todo links
+// and aliases.
+func (t *TagsRepositoryImpl) DeletePermanently(ctx context.Context, tagsId int, version int, strategy string) error {
+	return t.delete(ctx, tagsId, version, strategy, true)
+}
+
+func (t *TagsRepositoryImpl) delete(ctx context.Context, tagsId int, version int, strategy string, unscoped bool) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		if unscoped {
+			tx = tx.Unscoped().Session(&gorm.Session{})
+		} else {
+			deletedAt := tx.NowFunc()
+			tx = tx.Session(&gorm.Session{NowFunc: func() time.Time { return deletedAt }})
+		}
+
+		var tag model.Tags
+		result := tx.First(&tag, tagsId)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return helper.ErrNotFound
+		} else if result.Error != nil {
+			return result.Error
+		}
+
+		query := tx.Where("id = ?", tagsId)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		deleteResult := query.Delete(&model.Tags{})
+		if deleteResult.Error != nil {
+			return deleteResult.Error
+		}
+		if deleteResult.RowsAffected == 0 {
+			return helper.ErrPreconditionFailed
+		}
+
+		removed := []int{tagsId}
+		children := tx.Model(&model.Tags{}).Where("parent_id = ?", tagsId)
+		switch strategy {
+		case data.DeleteCascade:
+			ids, err := descendantIds(tx, tagsId, unscoped)
+			if err != nil {
+				return err
+			}
+			if len(ids) > 0 {
+				if err := tx.Where("id IN ?", ids).Delete(&model.Tags{}).Error; err != nil {
+					return err
+				}
+			}
+			logging.FromContext(ctx).DebugContext(ctx, "deleting tag with descendants", "tag_id", tagsId, "descendants", len(ids))
+			removed = append(removed, ids...)
+		case data.DeleteReparent:
+			result := children.Updates(map[string]interface{}{
+				"parent_id": tag.ParentId,
+				"version":   gorm.Expr("version + 1"),
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+		default:
+			var count int64
+			if err := children.Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return helper.ErrHasChildren
+			}
+		}
+
+		if !unscoped {
+			return nil
+		}
+		if err := tx.Where("tag_id IN ?", removed).Delete(&model.Tagging{}).Error; err != nil {
+			return err
+		}
+		if err := withoutTenant(tx).Where("tag_id IN ?", removed).Delete(&model.TodoTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("tag_id IN ?", removed).Delete(&model.TagAlias{}).Error
+	})
+}
+
+func (t *TagsRepositoryImpl) FindTrash(ctx context.Context, query data.TagQuery) ([]model.Tags, int64, error) {
+	trash := t.scoped(ctx).Unscoped().Where("deleted_at IS NOT NULL").Scopes(tagFilters(query))
+
+	var total int64
+	result := trash.Session(&gorm.Session{}).Model(&model.Tags{}).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	order, ok := tagSortColumns[query.Sort]
+	if !ok {
+		order = "deleted_at DESC, id DESC"
+	}
+
+	var tags []model.Tags
+	result = trash.Session(&gorm.Session{}).
+		Order(order).
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&tags)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return tags, total, nil
+}
+
+// Restore takes a tag out of the trash together with the descendants a cascading delete trashed along with it,
+// the ones sharing its deleted_at. Descendants trashed on their own stay in the trash. Nothing is restored when
+// one of the names has become an alias of another tag in the meantime.
+func (t *TagsRepositoryImpl) Restore(ctx context.Context, tagsId int) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		var tag model.Tags
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", tagsId).First(&tag)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return helper.ErrNotFound
+		} else if result.Error != nil {
+			return result.Error
+		}
+
+		restored := []int{tagsId}
+		ids, err := descendantIds(tx, tagsId, true)
+		if err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			var cascaded []int
+			result := tx.Unscoped().Model(&model.Tags{}).
+				Where("id IN ?", ids).
+				Where("deleted_at = (?)", tx.Unscoped().Model(&model.Tags{}).Select("deleted_at").Where("id = ?", tagsId)).
+				Pluck("id", &cascaded)
+			if result.Error != nil {
+				return result.Error
+			}
+			restored = append(restored, cascaded...)
+		}
+
+		var aliased int64
+		result = tx.Model(&model.TagAlias{}).
+			Where("normalized_name IN (?)", tx.Unscoped().Model(&model.Tags{}).Select("normalized_name").Where("id IN ?", restored)).
+			Count(&aliased)
+		if result.Error != nil {
+			return result.Error
+		}
+		if aliased > 0 {
+			return helper.ErrConflict
+		}
+
+		result = tx.Unscoped().
+			Model(&model.Tags{}).
+			Where("id IN ?", restored).
+			Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return translateError(t.Db, result.Error)
+		}
+		logging.FromContext(ctx).DebugContext(ctx, "restoring tag with descendants", "tag_id", tagsId, "descendants", len(restored)-1)
+		return nil
+	})
+}
+
+// PurgeTrash permanently removes tags that were moved to the trash before deletedBefore.
+// Tags left under a purged parent become roots; taggings, todo links and aliases of purged tags are dropped.
+func (t *TagsRepositoryImpl) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Unscoped().Model(&model.Tags{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore)
+		result := tx.Unscoped().Model(&model.Tags{}).
+			Where("parent_id IN (?)", expired).
+			Updates(map[string]interface{}{"parent_id": nil, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Where("tag_id IN (?)", expired).Delete(&model.Tagging{}).Error; err != nil {
+			return err
+		}
+		if err := withoutTenant(tx).Where("tag_id IN (?)", expired).Delete(&model.TodoTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id IN (?)", expired).Delete(&model.TagAlias{}).Error; err != nil {
+			return err
+		}
+
+		reparented := result.RowsAffected
+
+		result = tx.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Delete(&model.Tags{})
+		purged = result.RowsAffected
+		logging.FromContext(ctx).DebugContext(ctx, "purging trash", "deleted_before", deletedBefore, "purged", purged,
+			"reparented", reparented)
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// SaveBatch inserts all tags in one transaction and returns them with their generated ids.
+func (t *TagsRepositoryImpl) SaveBatch(ctx context.Context, tags []model.Tags) ([]model.Tags, error) {
+	err := t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := takenNames(tx, tags); err != nil {
+			return err
+		}
+		return tx.CreateInBatches(&tags, batchSize).Error
+	})
+	if err != nil {
+		return nil, translateError(t.Db, err)
+	}
+	return tags, nil
+}
+
+// takenNames reports every tag whose name already belongs to an active tag or an alias as a
+// helper.BatchItemErrors, so a failed batch names all conflicting items rather than the first the insert hits.
+func takenNames(tx *gorm.DB, tags []model.Tags) error {
+	names := make([]string, len(tags))
+	for index, tag := range tags {
+		names[index] = model.NormalizeTagName(tag.Name)
+	}
+
+	var taken, aliased []string
+	if err := tx.Model(&model.Tags{}).Where("normalized_name IN ?", names).Pluck("normalized_name", &taken).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&model.TagAlias{}).Where("normalized_name IN ?", names).Pluck("normalized_name", &aliased).Error; err != nil {
+		return err
+	}
+	used := map[string]bool{}
+	for _, name := range append(taken, aliased...) {
+		used[name] = true
+	}
+
+	var failures helper.BatchItemErrors
+	for index, name := range names {
+		if used[name] {
+			failures = append(failures, &helper.BatchItemError{Index: index, Err: helper.ErrConflict})
+		}
+	}
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// UpdateBatch writes the fields Update writes for all tags in one transaction. Each row is conditional on its Version when it is non-zero;
+// the first failing row rolls back the batch and is reported as a *helper.BatchItemError.
+func (t *TagsRepositoryImpl) UpdateBatch(ctx context.Context, tags []model.Tags) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		for index, tag := range tags {
+			query := tx.Model(&tag)
+			if tag.Version != 0 {
+				query = query.Where("version = ?", tag.Version)
+			}
+			result := query.Updates(map[string]interface{}{
+				"name":        tag.Name,
+				"parent_id":   tag.ParentId,
+				"description": tag.Description,
+				"color":       tag.Color,
+				"icon":        tag.Icon,
+				"attributes":  tag.Attributes,
+				"version":     gorm.Expr("version + 1"),
+			})
+			if result.Error != nil {
+				return &helper.BatchItemError{Index: index, Err: translateError(tx, result.Error)}
+			}
+			if result.RowsAffected > 0 {
+				continue
+			}
+
+			var count int64
+			if err := tx.Model(&model.Tags{}).Where("id = ?", tag.Id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return &helper.BatchItemError{Index: index, Err: helper.ErrNotFound}
+			}
+			return &helper.BatchItemError{Index: index, Err: helper.ErrPreconditionFailed}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch moves all tags to the trash in one transaction, or none of them if any id does not exist
+// or still has children outside the batch. Every such id is reported in a helper.BatchItemErrors.
+func (t *TagsRepositoryImpl) DeleteBatch(ctx context.Context, tagIds []int) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []int
+		if err := tx.Model(&model.Tags{}).Where("id IN ?", tagIds).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		found := map[int]bool{}
+		for _, id := range existing {
+			found[id] = true
+		}
+
+		var parents []int
+		result := tx.Model(&model.Tags{}).
+			Where("parent_id IN ? AND id NOT IN ?", tagIds, tagIds).
+			Distinct().
+			Pluck("parent_id", &parents)
+		if result.Error != nil {
+			return result.Error
+		}
+		blocked := map[int]bool{}
+		for _, id := range parents {
+			blocked[id] = true
+		}
+
+		var failures helper.BatchItemErrors
+		for index, id := range tagIds {
+			if !found[id] {
+				failures = append(failures, &helper.BatchItemError{Index: index, Err: helper.ErrNotFound})
+			} else if blocked[id] {
+				failures = append(failures, &helper.BatchItemError{Index: index, Err: helper.ErrHasChildren})
+			}
+		}
+		if len(failures) > 0 {
+			return failures
+		}
+		return tx.Where("id IN ?", tagIds).Delete(&model.Tags{}).Error
+	})
+}
+
+// SaveAlias attaches an alias to a tag and bumps the tag's version. The alias may not be the name of an active tag.
+func (t *TagsRepositoryImpl) SaveAlias(ctx context.Context, alias model.TagAlias) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		result := tx.Model(&model.Tags{}).Where("normalized_name = ?", model.NormalizeTagName(alias.Name)).Count(&count)
+		if result.Error != nil {
+			return result.Error
+		}
+		if count > 0 {
+			return helper.ErrConflict
+		}
+
+		if err := tx.Create(&alias).Error; err != nil {
+			return translateError(tx, err)
+		}
+		return bumpVersion(tx, alias.TagId)
+	})
+}
+
+func (t *TagsRepositoryImpl) DeleteAlias(ctx context.Context, tagId int, name string) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("tag_id = ? AND normalized_name = ?", tagId, model.NormalizeTagName(name)).Delete(&model.TagAlias{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helper.ErrNotFound
+		}
+		return bumpVersion(tx, tagId)
+	})
+}
+
+func bumpVersion(db *gorm.DB, tagId int) error {
+	result := db.Model(&model.Tags{}).Where("id = ?", tagId).Update("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return helper.ErrNotFound
+	}
+	return nil
+}
+
+// Merge folds the source tags into the target in one transaction: their taggings, todos, aliases and children
+// move to the target, the sources are deleted for good and their names become aliases of the target. A non-zero version makes the merge
+// conditional on the target's version.
+func (t *TagsRepositoryImpl) Merge(ctx context.Context, targetId int, sourceIds []int, version int) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		var target model.Tags
+		result := tx.First(&target, targetId)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return helper.ErrNotFound
+		} else if result.Error != nil {
+			return result.Error
+		}
+
+		query := tx.Model(&model.Tags{}).Where("id = ?", targetId)
+		if version != 0 {
+			query = query.Where("version = ?", version)
+		}
+		result = query.Update("version", gorm.Expr("version + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helper.ErrPreconditionFailed
+		}
+
+		var sources []model.Tags
+		if err := tx.Where("id IN ?", sourceIds).Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) != len(sourceIds) {
+			return helper.ErrNotFound
+		}
+
+		if err := mergeTaggings(tx, targetId, sourceIds); err != nil {
+			return err
+		}
+		if err := mergeTodoTags(tx, targetId, sourceIds); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.TagAlias{}).Where("tag_id IN ?", sourceIds).Update("tag_id", targetId).Error; err != nil {
+			return err
+		}
+		result = tx.Unscoped().Model(&model.Tags{}).
+			Where("parent_id IN ? AND id NOT IN ?", sourceIds, sourceIds).
+			Updates(map[string]interface{}{"parent_id": targetId, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Unscoped().Where("id IN ?", sourceIds).Delete(&model.Tags{}).Error; err != nil {
+			return err
+		}
+
+		var existing []string
+		if err := tx.Model(&model.TagAlias{}).Where("tag_id = ?", targetId).Pluck("normalized_name", &existing).Error; err != nil {
+			return err
+		}
+		taken := map[string]bool{target.NormalizedName: true}
+		for _, name := range existing {
+			taken[name] = true
+		}
+		aliases := []model.TagAlias{}
+		for _, source := range sources {
+			if !taken[source.NormalizedName] {
+				aliases = append(aliases, model.TagAlias{TagId: targetId, Name: source.Name})
+			}
+		}
+		logging.FromContext(ctx).DebugContext(ctx, "merging tags", "tag_id", targetId, "source_ids", sourceIds,
+			"new_aliases", len(aliases))
+		if len(aliases) == 0 {
+			return nil
+		}
+		return translateError(tx, tx.Create(&aliases).Error)
+	})
+}
+
+// mergeTaggings moves the taggings of the sources to the target. A resource tagged more than once among them
+// keeps only one tagging, so the unique index holds.
+func mergeTaggings(tx *gorm.DB, targetId int, sourceIds []int) error {
+	result := tx.Where(`tag_id IN ? AND EXISTS (
+			SELECT 1 FROM taggings AS other
+			WHERE other.resource_type = taggings.resource_type AND other.resource_id = taggings.resource_id
+			AND (other.tag_id = ? OR (other.tag_id IN ? AND other.id < taggings.id))
+		)`, sourceIds, targetId, sourceIds).
+		Delete(&model.Tagging{})
+	if result.Error != nil {
+		return result.Error
+	}
+	return tx.Model(&model.Tagging{}).Where("tag_id IN ?", sourceIds).Update("tag_id", targetId).Error
+}
+
+// mergeTodoTags moves the todos of the sources to the target, linking each todo to it only once.
+// The caller has checked that all ids belong to the tenant.
+func mergeTodoTags(tx *gorm.DB, targetId int, sourceIds []int) error {
+	tx = withoutTenant(tx)
+	result := tx.Where(`tag_id IN ? AND EXISTS (
+			SELECT 1 FROM todo_tags AS other
+			WHERE other.todo_id = todo_tags.todo_id
+			AND (other.tag_id = ? OR (other.tag_id IN ? AND other.tag_id < todo_tags.tag_id))
+		)`, sourceIds, targetId, sourceIds).
+		Delete(&model.TodoTag{})
 	if result.Error != nil {
 		return result.Error
 	}
+	return tx.Model(&model.TodoTag{}).Where("tag_id IN ?", sourceIds).Update("tag_id", targetId).Error
+}
+
+// Attach tags a resource. Attaching a tag that is already there is not an error.
+func (t *TagsRepositoryImpl) Attach(ctx context.Context, tagging model.Tagging) error {
+	result := t.scoped(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tagging)
+	if result.Error != nil {
+		return translateError(t.Db, result.Error)
+	}
 	return nil
 }
 
-func (t *TagsRepositoryImpl) Delete(tagsId int) error {
-	deleteResult := t.Db.Delete(&model.Tags{}, tagsId)
-	if deleteResult.Error != nil {
-		return deleteResult.Error
+func (t *TagsRepositoryImpl) Detach(ctx context.Context, tagging model.Tagging) error {
+	result := t.scoped(ctx).
+		Where("tag_id = ? AND resource_type = ? AND resource_id = ?", tagging.TagId, tagging.ResourceType, tagging.ResourceId).
+		Delete(&model.Tagging{})
+	if result.Error != nil {
+		return result.Error
 	}
-	if deleteResult.RowsAffected == 0 {
+	if result.RowsAffected == 0 {
 		return helper.ErrNotFound
 	}
 	return nil
 }
+
+// FindByResource returns the active tags attached to a resource ordered by name.
+func (t *TagsRepositoryImpl) FindByResource(ctx context.Context, resourceType string, resourceId string) ([]model.Tags, error) {
+	var tags []model.Tags
+	result := t.scoped(ctx).
+		Joins("JOIN taggings ON taggings.tag_id = tags.id").
+		Where("taggings.resource_type = ? AND taggings.resource_id = ?", resourceType, resourceId).
+		Order("tags.name ASC, tags.id ASC").
+		Find(&tags)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tags, nil
+}
+
+// FindResources pages through the resources a tag is attached to, most recently tagged first.
+func (t *TagsRepositoryImpl) FindResources(ctx context.Context, tagId int, query data.TaggingQuery) ([]model.Tagging, int64, error) {
+	taggings := t.scoped(ctx).Model(&model.Tagging{}).Where("tag_id = ?", tagId)
+	if query.ResourceType != "" {
+		taggings = taggings.Where("resource_type = ?", query.ResourceType)
+	}
+
+	var total int64
+	result := taggings.Session(&gorm.Session{}).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	var resources []model.Tagging
+	result = taggings.Session(&gorm.Session{}).
+		Order("created_at DESC, id DESC").
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&resources)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return resources, total, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"go-gin-project/data"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/helper/tracing"
+	"go-gin-project/model"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// NewInstrumentedTagsRepository returns a TagsRepository that records the duration, errors and a span of every call.
+func NewInstrumentedTagsRepository(Db *gorm.DB, metrics *metrics.Metrics, tracerProvider trace.TracerProvider) TagsRepository {
+	return &InstrumentedTagsRepository{
+		next:    NewTagsRepositoryImpl(Db),
+		metrics: metrics,
+		tracer:  tracerProvider.Tracer(tracing.InstrumentationName),
+	}
+}
+
+// InstrumentedTagsRepository wraps a TagsRepository with metrics and tracing.
+type InstrumentedTagsRepository struct {
+	next    TagsRepository
+	metrics *metrics.Metrics
+	tracer  trace.Tracer
+}
+
+// track starts timing a call and a span named after it, returning the context to make the call with; defer the
+// returned function with the address of the call's error.
+func (r *InstrumentedTagsRepository) track(ctx context.Context, method string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := r.tracer.Start(ctx, "TagsRepository."+method)
+	return ctx, func(err *error) {
+		r.metrics.ObserveRepositoryCall("tags", method, time.Since(start), *err)
+		tracing.End(span, *err)
+	}
+}
+
+func (r *InstrumentedTagsRepository) Save(ctx context.Context, tag model.Tags) (err error) {
+	ctx, done := r.track(ctx, "Save")
+	defer done(&err)
+	return r.next.Save(ctx, tag)
+}
+
+func (r *InstrumentedTagsRepository) FindAll(ctx context.Context, query data.TagQuery) (tags []model.Tags, total int64, err error) {
+	ctx, done := r.track(ctx, "FindAll")
+	defer done(&err)
+	return r.next.FindAll(ctx, query)
+}
+
+func (r *InstrumentedTagsRepository) FindAllByCursor(ctx context.Context, query data.TagQuery, cursor *data.TagCursor) (tags []model.Tags, more bool, err error) {
+	ctx, done := r.track(ctx, "FindAllByCursor")
+	defer done(&err)
+	return r.next.FindAllByCursor(ctx, query, cursor)
+}
+
+func (r *InstrumentedTagsRepository) FindById(ctx context.Context, tagId int) (tag model.Tags, err error) {
+	ctx, done := r.track(ctx, "FindById")
+	defer done(&err)
+	return r.next.FindById(ctx, tagId)
+}
+
+func (r *InstrumentedTagsRepository) FindByName(ctx context.Context, name string) (tag model.Tags, err error) {
+	ctx, done := r.track(ctx, "FindByName")
+	defer done(&err)
+	return r.next.FindByName(ctx, name)
+}
+
+func (r *InstrumentedTagsRepository) Update(ctx context.Context, tag model.Tags) (err error) {
+	ctx, done := r.track(ctx, "Update")
+	defer done(&err)
+	return r.next.Update(ctx, tag)
+}
+
+func (r *InstrumentedTagsRepository) FindChildren(ctx context.Context, tagId int) (tags []model.Tags, err error) {
+	ctx, done := r.track(ctx, "FindChildren")
+	defer done(&err)
+	return r.next.FindChildren(ctx, tagId)
+}
+
+func (r *InstrumentedTagsRepository) FindAncestors(ctx context.Context, tagId int) (tags []model.Tags, err error) {
+	ctx, done := r.track(ctx, "FindAncestors")
+	defer done(&err)
+	return r.next.FindAncestors(ctx, tagId)
+}
+
+func (r *InstrumentedTagsRepository) FindTree(ctx context.Context, maxDepth int, limit int) (tags []model.Tags, truncated bool, err error) {
+	ctx, done := r.track(ctx, "FindTree")
+	defer done(&err)
+	return r.next.FindTree(ctx, maxDepth, limit)
+}
+
+func (r *InstrumentedTagsRepository) Delete(ctx context.Context, tagId int, version int, strategy string) (err error) {
+	ctx, done := r.track(ctx, "Delete")
+	defer done(&err)
+	return r.next.Delete(ctx, tagId, version, strategy)
+}
+
+func (r *InstrumentedTagsRepository) DeletePermanently(ctx context.Context, tagId int, version int, strategy string) (err error) {
+	ctx, done := r.track(ctx, "DeletePermanently")
+	defer done(&err)
+	return r.next.DeletePermanently(ctx, tagId, version, strategy)
+}
+
+func (r *InstrumentedTagsRepository) FindTrash(ctx context.Context, query data.TagQuery) (tags []model.Tags, total int64, err error) {
+	ctx, done := r.track(ctx, "FindTrash")
+	defer done(&err)
+	return r.next.FindTrash(ctx, query)
+}
+
+func (r *InstrumentedTagsRepository) Restore(ctx context.Context, tagId int) (err error) {
+	ctx, done := r.track(ctx, "Restore")
+	defer done(&err)
+	return r.next.Restore(ctx, tagId)
+}
+
+func (r *InstrumentedTagsRepository) PurgeTrash(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	ctx, done := r.track(ctx, "PurgeTrash")
+	defer done(&err)
+	return r.next.PurgeTrash(ctx, deletedBefore)
+}
+
+func (r *InstrumentedTagsRepository) SaveBatch(ctx context.Context, tags []model.Tags) (saved []model.Tags, err error) {
+	ctx, done := r.track(ctx, "SaveBatch")
+	defer done(&err)
+	return r.next.SaveBatch(ctx, tags)
+}
+
+func (r *InstrumentedTagsRepository) UpdateBatch(ctx context.Context, tags []model.Tags) (err error) {
+	ctx, done := r.track(ctx, "UpdateBatch")
+	defer done(&err)
+	return r.next.UpdateBatch(ctx, tags)
+}
+
+func (r *InstrumentedTagsRepository) DeleteBatch(ctx context.Context, tagIds []int) (err error) {
+	ctx, done := r.track(ctx, "DeleteBatch")
+	defer done(&err)
+	return r.next.DeleteBatch(ctx, tagIds)
+}
+
+func (r *InstrumentedTagsRepository) SaveAlias(ctx context.Context, alias model.TagAlias) (err error) {
+	ctx, done := r.track(ctx, "SaveAlias")
+	defer done(&err)
+	return r.next.SaveAlias(ctx, alias)
+}
+
+func (r *InstrumentedTagsRepository) DeleteAlias(ctx context.Context, tagId int, name string) (err error) {
+	ctx, done := r.track(ctx, "DeleteAlias")
+	defer done(&err)
+	return r.next.DeleteAlias(ctx, tagId, name)
+}
+
+func (r *InstrumentedTagsRepository) Merge(ctx context.Context, targetId int, sourceIds []int, version int) (err error) {
+	ctx, done := r.track(ctx, "Merge")
+	defer done(&err)
+	return r.next.Merge(ctx, targetId, sourceIds, version)
+}
+
+func (r *InstrumentedTagsRepository) Attach(ctx context.Context, tagging model.Tagging) (err error) {
+	ctx, done := r.track(ctx, "Attach")
+	defer done(&err)
+	return r.next.Attach(ctx, tagging)
+}
+
+func (r *InstrumentedTagsRepository) Detach(ctx context.Context, tagging model.Tagging) (err error) {
+	ctx, done := r.track(ctx, "Detach")
+	defer done(&err)
+	return r.next.Detach(ctx, tagging)
+}
+
+func (r *InstrumentedTagsRepository) FindByResource(ctx context.Context, resourceType string, resourceId string) (tags []model.Tags, err error) {
+	ctx, done := r.track(ctx, "FindByResource")
+	defer done(&err)
+	return r.next.FindByResource(ctx, resourceType, resourceId)
+}
+
+func (r *InstrumentedTagsRepository) FindResources(ctx context.Context, tagId int, query data.TaggingQuery) (taggings []model.Tagging, total int64, err error) {
+	ctx, done := r.track(ctx, "FindResources")
+	defer done(&err)
+	return r.next.FindResources(ctx, tagId, query)
+}
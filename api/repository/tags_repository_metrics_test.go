@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"go-gin-project/api/repository"
+	"go-gin-project/helper"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/helper/tracing"
+	"go-gin-project/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestInstrumentedTagsRepository(t *testing.T) {
+	m := metrics.New()
+	repo := repository.NewInstrumentedTagsRepository(setupTestDB(), m, noop.NewTracerProvider())
+
+	assert.Nil(t, repo.Save(ctx, model.Tags{Name: "Tag1"}))
+	tag, err := repo.FindById(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Tag1", tag.Name)
+	_, err = repo.FindById(ctx, 99)
+	assert.ErrorIs(t, err, helper.ErrNotFound)
+
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `repository_call_duration_seconds_count{method="Save",repository="tags"} 1`)
+	assert.Contains(t, w.Body.String(), `repository_call_duration_seconds_count{method="FindById",repository="tags"} 2`)
+	assert.Contains(t, w.Body.String(), `repository_call_errors_total{kind="not_found",method="FindById",repository="tags"} 1`)
+	assert.NotContains(t, w.Body.String(), `repository_call_errors_total{kind="internal"`)
+}
+
+func TestInstrumentedTagsRepositoryTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	db := setupTestDB()
+	createMockData(db)
+	assert.Nil(t, db.Use(tracing.NewGormPlugin(provider)))
+	repo := repository.NewInstrumentedTagsRepository(db, metrics.New(), provider)
+
+	_, err := repo.FindById(ctx, 1)
+	assert.Nil(t, err)
+
+	spans := recorder.Ended()
+	assert.NotEmpty(t, spans)
+	call := spans[len(spans)-1]
+	assert.Equal(t, "TagsRepository.FindById", call.Name())
+	for _, query := range spans[:len(spans)-1] {
+		assert.Equal(t, call.SpanContext().SpanID(), query.Parent().SpanID())
+		assert.True(t, strings.HasPrefix(query.Name(), "SELECT "), query.Name())
+	}
+}
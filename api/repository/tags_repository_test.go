@@ -1,21 +1,29 @@
 package repository_test
 
 import (
+	"context"
 	"go-gin-project/api/repository"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/tenant"
 	"go-gin-project/model"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// ctx acts for the tenant that rows inserted without one fall back to, so fixtures created through db are visible.
+var ctx = tenant.NewContext(context.Background(), tenant.Default)
+
 func setupTestDB() *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		panic("failed to connect database")
 	}
-	db.AutoMigrate(&model.Tags{})
+	db.AutoMigrate(&model.Tags{}, &model.TagAlias{}, &model.Tagging{}, &model.Todo{}, &model.TodoTag{}, &model.ApiKey{})
 	return db
 }
 
@@ -32,7 +40,7 @@ func TestSaveTag(t *testing.T) {
 	repo := repository.NewTagsRepositoryImpl(db)
 	t.Run("should save new tag", func(t *testing.T) {
 		tag := model.Tags{Id: 3, Name: "TestTag"}
-		err := repo.Save(tag)
+		err := repo.Save(ctx, tag)
 		assert.Nil(t, err)
 
 		var count int64
@@ -40,12 +48,32 @@ func TestSaveTag(t *testing.T) {
 		assert.Equal(t, int64(1), count)
 	})
 
+	t.Run("should start new tags at version 1", func(t *testing.T) {
+		var tag model.Tags
+		db.First(&tag, 3)
+		assert.Equal(t, 1, tag.Version)
+	})
+
+	t.Run("should store normalized name", func(t *testing.T) {
+		err := repo.Save(ctx, model.Tags{Id: 5, Name: "  GoLang "})
+		assert.Nil(t, err)
+
+		var tag model.Tags
+		db.First(&tag, 5)
+		assert.Equal(t, "golang", tag.NormalizedName)
+	})
+
+	t.Run("should return conflict for name differing only in case", func(t *testing.T) {
+		err := repo.Save(ctx, model.Tags{Id: 6, Name: "golang"})
+		assert.ErrorIs(t, err, helper.ErrConflict)
+	})
+
 	t.Run("should return error when db is closed", func(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
 		tag := model.Tags{Id: 4, Name: "ErrorTag"}
-		err := repo.Save(tag)
+		err := repo.Save(ctx, tag)
 		assert.NotNil(t, err)
 	})
 }
@@ -55,32 +83,266 @@ func TestFindAllTags(t *testing.T) {
 	repo := repository.NewTagsRepositoryImpl(db)
 	t.Run("should return all tags", func(t *testing.T) {
 		createMockData(db)
-		tags, err := repo.FindAll()
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10})
 		assert.Nil(t, err)
 		assert.Len(t, tags, 2)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("should return error when db is closed", func(t *testing.T) {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+
+		_, _, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestFindAllTagsQuery(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewTagsRepositoryImpl(db)
+	db.Create(&[]model.Tags{
+		{Id: 1, Name: "golang"},
+		{Id: 2, Name: "Gin"},
+		{Id: 3, Name: "gorm"},
+		{Id: 4, Name: "python"},
+		{Id: 5, Name: "100%_done"},
+	})
+
+	t.Run("should paginate and report total", func(t *testing.T) {
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 2, PageSize: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), total)
+		assert.Len(t, tags, 2)
+		assert.Equal(t, 3, tags[0].Id)
+		assert.Equal(t, 4, tags[1].Id)
+	})
+
+	t.Run("should sort by id descending", func(t *testing.T) {
+		tags, _, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 2, Sort: "-id"})
+		assert.Nil(t, err)
+		assert.Equal(t, 5, tags[0].Id)
+		assert.Equal(t, 4, tags[1].Id)
+	})
+
+	t.Run("should sort by name", func(t *testing.T) {
+		tags, _, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, Sort: "name"})
+		assert.Nil(t, err)
+		assert.Equal(t, "100%_done", tags[0].Name)
+		assert.Equal(t, "python", tags[4].Name)
+	})
+
+	t.Run("should filter by name prefix case-insensitively", func(t *testing.T) {
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, NamePrefix: "G"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, tags, 3)
+	})
+
+	t.Run("should filter by name substring", func(t *testing.T) {
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, NameContains: "O"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Len(t, tags, 4)
+	})
+
+	t.Run("should treat wildcard characters literally", func(t *testing.T) {
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, NameContains: "%_"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "100%_done", tags[0].Name)
+	})
+}
+
+func TestFindAllTagsByTimestamps(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewTagsRepositoryImpl(db)
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	db.Create(&[]model.Tags{
+		{Id: 1, Name: "old", CreatedAt: base, UpdatedAt: base},
+		{Id: 2, Name: "edited", CreatedAt: base, UpdatedAt: base.Add(48 * time.Hour)},
+		{Id: 3, Name: "new", CreatedAt: base.Add(72 * time.Hour), UpdatedAt: base.Add(72 * time.Hour)},
+	})
+
+	t.Run("should set timestamps on save", func(t *testing.T) {
+		assert.Nil(t, repo.Save(ctx, model.Tags{Id: 4, Name: "fresh"}))
+
+		tag, err := repo.FindById(ctx, 4)
+		assert.Nil(t, err)
+		assert.False(t, tag.CreatedAt.IsZero())
+		assert.False(t, tag.UpdatedAt.IsZero())
+	})
+
+	t.Run("should filter by creation time", func(t *testing.T) {
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, CreatedAfter: base.Add(24 * time.Hour), UpdatedSince: base})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []int{3, 4}, tagIds(tags))
+	})
+
+	t.Run("should filter by update time in any zone", func(t *testing.T) {
+		since := base.Add(48 * time.Hour).In(time.FixedZone("CST", 8*3600))
+		tags, _, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 3, UpdatedSince: since})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2, 3, 4}, tagIds(tags))
+	})
+}
+
+func TestTrash(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewTagsRepositoryImpl(db)
+	createMockData(db)
+	db.Create(&model.Tags{Id: 3, Name: "Tag3"})
+
+	t.Run("should hide trashed tags from listing", func(t *testing.T) {
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteReject))
+
+		tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []int{2, 3}, tagIds(tags))
+	})
+
+	t.Run("should list only trashed tags", func(t *testing.T) {
+		tags, total, err := repo.FindTrash(ctx, data.TagQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []int{1}, tagIds(tags))
+		assert.True(t, tags[0].DeletedAt.Valid)
+	})
+
+	t.Run("should allow reusing a trashed name", func(t *testing.T) {
+		err := repo.Save(ctx, model.Tags{Id: 4, Name: "tag1"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("should refuse restore when the name was reused", func(t *testing.T) {
+		err := repo.Restore(ctx, 1)
+		assert.ErrorIs(t, err, helper.ErrConflict)
+	})
+
+	t.Run("should restore a trashed tag", func(t *testing.T) {
+		assert.Nil(t, repo.DeletePermanently(ctx, 4, 0, data.DeleteReject))
+		assert.Nil(t, repo.Restore(ctx, 1))
+
+		tag, err := repo.FindById(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Tag1", tag.Name)
+		assert.Equal(t, 2, tag.Version)
+	})
+
+	t.Run("should return not found when restoring a live tag", func(t *testing.T) {
+		err := repo.Restore(ctx, 2)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should delete permanently", func(t *testing.T) {
+		assert.Nil(t, repo.DeletePermanently(ctx, 2, 0, data.DeleteReject))
+
+		var count int64
+		db.Unscoped().Model(&model.Tags{}).Where("id = ?", 2).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("should purge only trash older than the cutoff", func(t *testing.T) {
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteReject))
+		assert.Nil(t, repo.Delete(ctx, 3, 0, data.DeleteReject))
+		db.Unscoped().Model(&model.Tags{}).Where("id = ?", 1).Update("deleted_at", time.Now().Add(-48*time.Hour))
+
+		purged, err := repo.PurgeTrash(ctx, time.Now().Add(-24*time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		tags, _, _ := repo.FindTrash(ctx, data.TagQuery{Page: 1, PageSize: 10})
+		assert.Equal(t, []int{3}, tagIds(tags))
 	})
 
 	t.Run("should return error when db is closed", func(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
-		_, err := repo.FindAll()
+		_, _, err := repo.FindTrash(ctx, data.TagQuery{Page: 1, PageSize: 10})
+		assert.NotNil(t, err)
+		_, err = repo.PurgeTrash(ctx, time.Now())
 		assert.NotNil(t, err)
 	})
 }
 
+func TestFindAllTagsByCursor(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewTagsRepositoryImpl(db)
+	db.Create(&[]model.Tags{
+		{Id: 1, Name: "go"},
+		{Id: 2, Name: "gin"},
+		{Id: 3, Name: "gorm"},
+		{Id: 4, Name: "gin-contrib"},
+		{Id: 5, Name: "air"},
+	})
+
+	t.Run("should seek forward by id", func(t *testing.T) {
+		tags, hasMore, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "id"}, &data.TagCursor{Id: 2})
+		assert.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, []int{3, 4}, tagIds(tags))
+	})
+
+	t.Run("should seek forward by id descending", func(t *testing.T) {
+		tags, hasMore, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "-id"}, &data.TagCursor{Id: 2})
+		assert.Nil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, []int{1}, tagIds(tags))
+	})
+
+	t.Run("should seek forward by name", func(t *testing.T) {
+		tags, hasMore, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "name"}, &data.TagCursor{Id: 2, Name: "gin"})
+		assert.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, []int{4, 1}, tagIds(tags))
+	})
+
+	t.Run("should seek backward and keep display order", func(t *testing.T) {
+		tags, hasMore, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "name"}, &data.TagCursor{Id: 1, Name: "go", Backward: true})
+		assert.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, []int{2, 4}, tagIds(tags))
+	})
+
+	t.Run("should start from the beginning without cursor", func(t *testing.T) {
+		tags, hasMore, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 5, Sort: "-name"}, nil)
+		assert.Nil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, []int{3, 1, 4, 2, 5}, tagIds(tags))
+	})
+
+	t.Run("should return error when db is closed", func(t *testing.T) {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+
+		_, _, err := repo.FindAllByCursor(ctx, data.TagQuery{PageSize: 2}, nil)
+		assert.NotNil(t, err)
+	})
+}
+
+func tagIds(tags []model.Tags) []int {
+	ids := []int{}
+	for _, tag := range tags {
+		ids = append(ids, tag.Id)
+	}
+	return ids
+}
+
 func TestFindTagById(t *testing.T) {
 	db := setupTestDB()
 	repo := repository.NewTagsRepositoryImpl(db)
 	createMockData(db)
 	t.Run("should find existing tag", func(t *testing.T) {
-		tag, err := repo.FindById("1")
+		tag, err := repo.FindById(ctx, 1)
 		assert.Nil(t, err)
 		assert.Equal(t, "Tag1", tag.Name)
 	})
 
 	t.Run("should return not found for non-existent tag", func(t *testing.T) {
-		_, err := repo.FindById("999")
+		_, err := repo.FindById(ctx, 999)
 		assert.NotNil(t, err)
 		assert.Equal(t, "resource not found", err.Error())
 	})
@@ -89,7 +351,7 @@ func TestFindTagById(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
-		_, err := repo.FindById("1")
+		_, err := repo.FindById(ctx, 1)
 		assert.NotNil(t, err)
 	})
 }
@@ -99,21 +361,42 @@ func TestUpdateTag(t *testing.T) {
 	repo := repository.NewTagsRepositoryImpl(db)
 	t.Run("should update existing tag", func(t *testing.T) {
 		createMockData(db)
-		updatedTag := model.Tags{Id: 1, Name: "UpdatedTag"}
-		err := repo.Update(updatedTag)
+		updatedTag := model.Tags{Id: 1, Name: "UpdatedTag", Version: 1}
+		err := repo.Update(ctx, updatedTag)
 		assert.Nil(t, err)
 
 		var tag model.Tags
 		db.First(&tag, "1")
 		assert.Equal(t, "UpdatedTag", tag.Name)
+		assert.Equal(t, "updatedtag", tag.NormalizedName)
+		assert.Equal(t, 2, tag.Version)
+	})
+
+	t.Run("should reject an update based on a stale version", func(t *testing.T) {
+		err := repo.Update(ctx, model.Tags{Id: 1, Name: "LostUpdate", Version: 1})
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+
+		var tag model.Tags
+		db.First(&tag, "1")
+		assert.Equal(t, "UpdatedTag", tag.Name)
+	})
+
+	t.Run("should return conflict when renaming onto an existing name", func(t *testing.T) {
+		err := repo.Update(ctx, model.Tags{Id: 1, Name: "TAG2", Version: 2})
+		assert.ErrorIs(t, err, helper.ErrConflict)
+	})
+
+	t.Run("should allow changing the case of its own name", func(t *testing.T) {
+		err := repo.Update(ctx, model.Tags{Id: 1, Name: "UPDATEDTAG", Version: 2})
+		assert.Nil(t, err)
 	})
 
 	t.Run("should return error when db is closed", func(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
-		updatedTag := model.Tags{Id: 1, Name: "ErrorTag"}
-		err := repo.Update(updatedTag)
+		updatedTag := model.Tags{Id: 1, Name: "ErrorTag", Version: 3}
+		err := repo.Update(ctx, updatedTag)
 		assert.NotNil(t, err)
 	})
 }
@@ -123,7 +406,7 @@ func TestDeleteTag(t *testing.T) {
 	repo := repository.NewTagsRepositoryImpl(db)
 	t.Run("should delete existing tag", func(t *testing.T) {
 		createMockData(db)
-		err := repo.Delete(1)
+		err := repo.Delete(ctx, 1, 0, data.DeleteReject)
 		assert.Nil(t, err)
 
 		var count int64
@@ -131,8 +414,30 @@ func TestDeleteTag(t *testing.T) {
 		assert.Equal(t, int64(0), count)
 	})
 
+	t.Run("should keep deleted tag in trash", func(t *testing.T) {
+		var tag model.Tags
+		db.Unscoped().First(&tag, 1)
+		assert.True(t, tag.DeletedAt.Valid)
+
+		_, err := repo.FindById(ctx, 1)
+		assert.Equal(t, "resource not found", err.Error())
+	})
+
+	t.Run("should reject delete with a stale version", func(t *testing.T) {
+		err := repo.Delete(ctx, 2, 5, data.DeleteReject)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+
+		err = repo.Delete(ctx, 2, 1, data.DeleteReject)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should return not found when deleting a trashed tag again", func(t *testing.T) {
+		err := repo.Delete(ctx, 1, 0, data.DeleteReject)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
 	t.Run("should return not found error for non-existent tag", func(t *testing.T) {
-		err := repo.Delete(999)
+		err := repo.Delete(ctx, 999, 0, data.DeleteReject)
 		assert.NotNil(t, err)
 		assert.Equal(t, "resource not found", err.Error())
 	})
@@ -141,7 +446,711 @@ func TestDeleteTag(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
-		err := repo.Delete(1)
+		err := repo.Delete(ctx, 1, 0, data.DeleteReject)
 		assert.NotNil(t, err)
 	})
 }
+
+func TestBatchOperations(t *testing.T) {
+	db := setupTestDB()
+	repo := repository.NewTagsRepositoryImpl(db)
+	createMockData(db)
+
+	t.Run("should insert a batch and return ids", func(t *testing.T) {
+		saved, err := repo.SaveBatch(ctx, []model.Tags{{Name: "batch-one"}, {Name: "batch-two"}})
+		assert.Nil(t, err)
+		assert.Len(t, saved, 2)
+		assert.NotZero(t, saved[0].Id)
+		assert.NotZero(t, saved[1].Id)
+		assert.Equal(t, "batch-two", saved[1].NormalizedName)
+	})
+
+	t.Run("should roll back the whole batch on conflict", func(t *testing.T) {
+		_, err := repo.SaveBatch(ctx, []model.Tags{{Name: "batch-three"}, {Name: "TAG1"}})
+		assert.ErrorIs(t, err, helper.ErrConflict)
+
+		var count int64
+		db.Model(&model.Tags{}).Where("name = ?", "batch-three").Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("should name every item whose name or alias is taken", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 2, Name: "Second"}))
+
+		_, err := repo.SaveBatch(ctx, []model.Tags{{Name: "tag1"}, {Name: "batch-four"}, {Name: "SECOND"}})
+
+		var itemErrs helper.BatchItemErrors
+		assert.ErrorAs(t, err, &itemErrs)
+		assert.Len(t, itemErrs, 2)
+		assert.Equal(t, 0, itemErrs[0].Index)
+		assert.Equal(t, 2, itemErrs[1].Index)
+		assert.ErrorIs(t, err, helper.ErrConflict)
+	})
+
+	t.Run("should update a batch and bump versions", func(t *testing.T) {
+		err := repo.UpdateBatch(ctx, []model.Tags{{Id: 1, Name: "Renamed1", Version: 1}, {Id: 2, Name: "Renamed2"}})
+		assert.Nil(t, err)
+
+		var tags []model.Tags
+		db.Order("id").Find(&tags, []int{1, 2})
+		assert.Equal(t, "Renamed1", tags[0].Name)
+		assert.Equal(t, "renamed2", tags[1].NormalizedName)
+		assert.Equal(t, 2, tags[1].Version)
+	})
+
+	t.Run("should roll back a batch update and name the failing item", func(t *testing.T) {
+		err := repo.UpdateBatch(ctx, []model.Tags{{Id: 1, Name: "Lost1"}, {Id: 2, Name: "Lost2", Version: 1}})
+
+		var itemErr *helper.BatchItemError
+		assert.ErrorAs(t, err, &itemErr)
+		assert.Equal(t, 1, itemErr.Index)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+
+		tag, _ := repo.FindById(ctx, 1)
+		assert.Equal(t, "Renamed1", tag.Name)
+	})
+
+	t.Run("should write every editable field in a batch update", func(t *testing.T) {
+		parentId := 1
+		err := repo.UpdateBatch(ctx, []model.Tags{{Id: 2, Name: "Renamed2", ParentId: &parentId, Description: "Docs",
+			Color: "#ff0000", Icon: "book", Attributes: model.TagAttributes{"team": "web"}}})
+		assert.Nil(t, err)
+
+		tag, _ := repo.FindById(ctx, 2)
+		assert.Equal(t, &parentId, tag.ParentId)
+		assert.Equal(t, "Docs", tag.Description)
+		assert.Equal(t, "#ff0000", tag.Color)
+		assert.Equal(t, "book", tag.Icon)
+		assert.Equal(t, model.TagAttributes{"team": "web"}, tag.Attributes)
+	})
+
+	t.Run("should report a missing tag in a batch update", func(t *testing.T) {
+		err := repo.UpdateBatch(ctx, []model.Tags{{Id: 999, Name: "Missing"}})
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should roll back a batch delete when an id is missing", func(t *testing.T) {
+		err := repo.DeleteBatch(ctx, []int{1, 999})
+
+		var itemErrs helper.BatchItemErrors
+		assert.ErrorAs(t, err, &itemErrs)
+		assert.Len(t, itemErrs, 2)
+		assert.Equal(t, 0, itemErrs[0].Index)
+		assert.ErrorIs(t, itemErrs[0], helper.ErrHasChildren)
+		assert.Equal(t, 1, itemErrs[1].Index)
+		assert.ErrorIs(t, itemErrs[1], helper.ErrNotFound)
+
+		_, err = repo.FindById(ctx, 1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should move a batch to the trash", func(t *testing.T) {
+		err := repo.DeleteBatch(ctx, []int{1, 2})
+		assert.Nil(t, err)
+
+		tags, _, _ := repo.FindTrash(ctx, data.TagQuery{Page: 1, PageSize: 10, Sort: "id"})
+		assert.Equal(t, []int{1, 2}, tagIds(tags))
+	})
+}
+
+func createMockHierarchy(db *gorm.DB) {
+	lang, golang, web := 1, 2, 3
+	mockTags := []model.Tags{
+		{Id: 1, Name: "lang"},
+		{Id: 2, Name: "golang", ParentId: &lang},
+		{Id: 3, Name: "web"},
+		{Id: 4, Name: "gin", ParentId: &golang},
+		{Id: 5, Name: "echo", ParentId: &golang},
+		{Id: 6, Name: "http", ParentId: &web},
+	}
+	db.Create(&mockTags)
+}
+
+func TestHierarchy(t *testing.T) {
+	t.Run("should find direct children by name", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tags, err := repo.FindChildren(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5, 4}, tagIds(tags))
+	})
+
+	t.Run("should find the tree level by level", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tags, truncated, err := repo.FindTree(ctx, data.MaxTreeDepth, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.False(t, truncated)
+		assert.Equal(t, []int{1, 3, 2, 6, 5, 4}, tagIds(tags))
+	})
+
+	t.Run("should leave out tags below the max depth", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tags, truncated, err := repo.FindTree(ctx, 2, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.True(t, truncated)
+		assert.Equal(t, []int{1, 3, 2, 6}, tagIds(tags))
+
+		tags, truncated, err = repo.FindTree(ctx, 3, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.False(t, truncated)
+		assert.Len(t, tags, 6)
+	})
+
+	t.Run("should stop the tree at the node limit", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tags, truncated, err := repo.FindTree(ctx, data.MaxTreeDepth, 3)
+		assert.Nil(t, err)
+		assert.True(t, truncated)
+		assert.Equal(t, []int{1, 3, 2}, tagIds(tags))
+
+		tags, truncated, err = repo.FindTree(ctx, data.MaxTreeDepth, 6)
+		assert.Nil(t, err)
+		assert.False(t, truncated)
+		assert.Len(t, tags, 6)
+	})
+
+	t.Run("should find ancestors from the root down", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tags, err := repo.FindAncestors(ctx, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, tagIds(tags))
+
+		tags, err = repo.FindAncestors(ctx, 1)
+		assert.Nil(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("should move a tag by updating its parent", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		tag, _ := repo.FindById(ctx, 4)
+		tag.ParentId = nil
+		assert.Nil(t, repo.Update(ctx, tag))
+
+		tag, _ = repo.FindById(ctx, 4)
+		assert.Nil(t, tag.ParentId)
+		assert.Equal(t, 2, tag.Version)
+	})
+
+	t.Run("should reject deleting a tag with children", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		err := repo.Delete(ctx, 2, 0, data.DeleteReject)
+		assert.ErrorIs(t, err, helper.ErrHasChildren)
+
+		_, err = repo.FindById(ctx, 2)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should check the version before the children", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		err := repo.Delete(ctx, 2, 5, data.DeleteReject)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+	})
+
+	t.Run("should trash all descendants on cascade", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteCascade))
+
+		tags, truncated, err := repo.FindTree(ctx, data.MaxTreeDepth, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.False(t, truncated)
+		assert.ElementsMatch(t, []int{3, 6}, tagIds(tags))
+	})
+
+	t.Run("should restore the descendants trashed by a cascade", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.Delete(ctx, 4, 0, data.DeleteReject))
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteCascade))
+		assert.Nil(t, repo.Restore(ctx, 1))
+
+		tags, _, err := repo.FindTree(ctx, data.MaxTreeDepth, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []int{1, 2, 3, 5, 6}, tagIds(tags))
+
+		golang, err := repo.FindById(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, golang.Version)
+		_, err = repo.FindById(ctx, 4)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should restore nothing when a cascaded name became an alias", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteCascade))
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 3, Name: "Gin"}))
+
+		assert.ErrorIs(t, repo.Restore(ctx, 1), helper.ErrConflict)
+		_, err := repo.FindById(ctx, 1)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should hand children to the grandparent on reparent", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.Delete(ctx, 2, 0, data.DeleteReparent))
+
+		tags, err := repo.FindChildren(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{5, 4}, tagIds(tags))
+		assert.Equal(t, 2, tags[0].Version)
+	})
+
+	t.Run("should reparent to root when the deleted tag is a root", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.DeletePermanently(ctx, 3, 0, data.DeleteReparent))
+
+		tag, _ := repo.FindById(ctx, 6)
+		assert.Nil(t, tag.ParentId)
+	})
+
+	t.Run("should follow trashed descendants when deleting permanently", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.Nil(t, repo.Delete(ctx, 4, 0, data.DeleteReject))
+		assert.Nil(t, repo.DeletePermanently(ctx, 2, 0, data.DeleteCascade))
+
+		var count int64
+		db.Unscoped().Model(&model.Tags{}).Count(&count)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("should detach children of purged tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		db.Model(&model.Tags{}).Where("id = ?", 3).Update("deleted_at", time.Now().Add(-time.Hour))
+		purged, err := repo.PurgeTrash(ctx, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+
+		tag, _ := repo.FindById(ctx, 6)
+		assert.Nil(t, tag.ParentId)
+	})
+
+	t.Run("should reject a bulk delete that leaves children behind", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		err := repo.DeleteBatch(ctx, []int{3, 2, 999})
+		var itemErrs helper.BatchItemErrors
+		assert.ErrorAs(t, err, &itemErrs)
+		assert.Len(t, itemErrs, 3)
+		assert.Equal(t, 0, itemErrs[0].Index)
+		assert.ErrorIs(t, itemErrs[0], helper.ErrHasChildren)
+		assert.Equal(t, 1, itemErrs[1].Index)
+		assert.ErrorIs(t, itemErrs[1], helper.ErrHasChildren)
+		assert.Equal(t, 2, itemErrs[2].Index)
+		assert.ErrorIs(t, itemErrs[2], helper.ErrNotFound)
+
+		assert.Nil(t, repo.DeleteBatch(ctx, []int{3, 6}))
+	})
+}
+
+func TestAliases(t *testing.T) {
+	t.Run("should resolve a tag by name or alias", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 1, Name: "T1"}))
+
+		tag, err := repo.FindByName(ctx, "t1")
+		assert.Nil(t, err)
+		assert.Equal(t, 1, tag.Id)
+		assert.Equal(t, "T1", tag.Aliases[0].Name)
+		assert.Equal(t, 2, tag.Version)
+
+		tag, err = repo.FindByName(ctx, " TAG2 ")
+		assert.Nil(t, err)
+		assert.Equal(t, 2, tag.Id)
+
+		_, err = repo.FindByName(ctx, "missing")
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+
+	t.Run("should keep aliases and tag names apart", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 1, Name: "T1"}))
+
+		assert.ErrorIs(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 1, Name: "tag2"}), helper.ErrConflict)
+		assert.ErrorIs(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 2, Name: "t1"}), helper.ErrConflict)
+		assert.ErrorIs(t, repo.Save(ctx, model.Tags{Name: "T1"}), helper.ErrConflict)
+
+		tag, _ := repo.FindById(ctx, 2)
+		tag.Name = "t1"
+		assert.ErrorIs(t, repo.Update(ctx, tag), helper.ErrConflict)
+	})
+
+	t.Run("should not restore a tag whose name became an alias", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, repo.Delete(ctx, 2, 0, data.DeleteReject))
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 1, Name: "tag2"}))
+
+		assert.ErrorIs(t, repo.Restore(ctx, 2), helper.ErrConflict)
+	})
+
+	t.Run("should delete an alias", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 1, Name: "T1"}))
+
+		assert.Nil(t, repo.DeleteAlias(ctx, 1, "t1"))
+		assert.ErrorIs(t, repo.DeleteAlias(ctx, 1, "t1"), helper.ErrNotFound)
+
+		_, err := repo.FindByName(ctx, "t1")
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+	})
+}
+
+func TestMergeTags(t *testing.T) {
+	t.Run("should fold sources into the target", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 2, Name: "go"}))
+
+		assert.Nil(t, repo.Merge(ctx, 3, []int{2}, 1))
+
+		target, err := repo.FindByName(ctx, "golang")
+		assert.Nil(t, err)
+		assert.Equal(t, 3, target.Id)
+		assert.Equal(t, 2, target.Version)
+		assert.ElementsMatch(t, []string{"go", "golang"}, []string{target.Aliases[0].Name, target.Aliases[1].Name})
+
+		children, _ := repo.FindChildren(ctx, 3)
+		assert.Equal(t, []int{5, 4, 6}, tagIds(children))
+
+		var count int64
+		db.Unscoped().Model(&model.Tags{}).Where("id = ?", 2).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("should roll back when a source is missing", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.ErrorIs(t, repo.Merge(ctx, 3, []int{2, 99}, 0), helper.ErrNotFound)
+
+		_, err := repo.FindById(ctx, 2)
+		assert.Nil(t, err)
+		target, _ := repo.FindById(ctx, 3)
+		assert.Equal(t, 1, target.Version)
+	})
+
+	t.Run("should reject a stale target version", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+
+		assert.ErrorIs(t, repo.Merge(ctx, 3, []int{2}, 7), helper.ErrPreconditionFailed)
+		assert.ErrorIs(t, repo.Merge(ctx, 99, []int{2}, 0), helper.ErrNotFound)
+	})
+}
+
+func attach(repo repository.TagsRepository, tagId int, resourceType string, resourceId string) error {
+	return repo.Attach(ctx, model.Tagging{TagId: tagId, ResourceType: resourceType, ResourceId: resourceId})
+}
+
+func TestTaggings(t *testing.T) {
+	t.Run("should attach tags idempotently and list them by resource", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+
+		assert.Nil(t, attach(repo, 2, "article", "a-1"))
+		assert.Nil(t, attach(repo, 1, "article", "a-1"))
+		assert.Nil(t, attach(repo, 1, "article", "a-1"))
+		assert.Nil(t, attach(repo, 1, "photo", "a-1"))
+
+		tags, err := repo.FindByResource(ctx, "article", "a-1")
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, tagIds(tags))
+	})
+
+	t.Run("should page through resources of a tag", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		for _, id := range []string{"a-1", "a-2", "a-3"} {
+			assert.Nil(t, attach(repo, 1, "article", id))
+		}
+		assert.Nil(t, attach(repo, 1, "photo", "p-1"))
+
+		resources, total, err := repo.FindResources(ctx, 1, data.TaggingQuery{Page: 1, PageSize: 2, ResourceType: "article"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, resources, 2)
+		assert.Equal(t, "a-3", resources[0].ResourceId)
+
+		resources, total, err = repo.FindResources(ctx, 1, data.TaggingQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Len(t, resources, 4)
+	})
+
+	t.Run("should detach a tag", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, attach(repo, 1, "article", "a-1"))
+
+		tagging := model.Tagging{TagId: 1, ResourceType: "article", ResourceId: "a-1"}
+		assert.Nil(t, repo.Detach(ctx, tagging))
+		assert.ErrorIs(t, repo.Detach(ctx, tagging), helper.ErrNotFound)
+	})
+
+	t.Run("should hide taggings of trashed tags and bring them back on restore", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockData(db)
+		assert.Nil(t, attach(repo, 1, "article", "a-1"))
+
+		assert.Nil(t, repo.Delete(ctx, 1, 0, data.DeleteReject))
+		tags, _ := repo.FindByResource(ctx, "article", "a-1")
+		assert.Empty(t, tags)
+
+		assert.Nil(t, repo.Restore(ctx, 1))
+		tags, _ = repo.FindByResource(ctx, "article", "a-1")
+		assert.Equal(t, []int{1}, tagIds(tags))
+	})
+
+	t.Run("should drop taggings with permanently deleted tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+		assert.Nil(t, attach(repo, 2, "article", "a-1"))
+		assert.Nil(t, attach(repo, 4, "article", "a-1"))
+		assert.Nil(t, attach(repo, 3, "article", "a-1"))
+
+		assert.Nil(t, repo.DeletePermanently(ctx, 2, 0, data.DeleteCascade))
+
+		var count int64
+		db.Model(&model.Tagging{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("should move taggings on merge without duplicates", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+		assert.Nil(t, attach(repo, 3, "article", "a-1"))
+		assert.Nil(t, attach(repo, 4, "article", "a-1"))
+		assert.Nil(t, attach(repo, 5, "article", "a-1"))
+		assert.Nil(t, attach(repo, 5, "article", "a-2"))
+		assert.Nil(t, attach(repo, 6, "article", "a-2"))
+
+		assert.Nil(t, repo.Merge(ctx, 3, []int{4, 5, 6}, 0))
+
+		resources, total, err := repo.FindResources(ctx, 3, data.TaggingQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.ElementsMatch(t, []string{"a-1", "a-2"}, []string{resources[0].ResourceId, resources[1].ResourceId})
+	})
+}
+
+func TestTagMetadata(t *testing.T) {
+	setup := func() (*gorm.DB, repository.TagsRepository) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		tags := []model.Tags{
+			{Name: "platform", Color: "#336699", Attributes: model.TagAttributes{"team": "platform", "public": true, "weight": 2}},
+			{Name: "frontend", Attributes: model.TagAttributes{"team": "web", "public": false}},
+			{Name: "backend", Attributes: model.TagAttributes{"team": "platform", "public": false}},
+			{Name: "plain"},
+		}
+		for _, tag := range tags {
+			repo.Save(ctx, tag)
+		}
+		return db, repo
+	}
+
+	t.Run("should store and load metadata", func(t *testing.T) {
+		_, repo := setup()
+
+		tag, err := repo.FindById(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "#336699", tag.Color)
+		assert.Equal(t, model.TagAttributes{"team": "platform", "public": true, "weight": float64(2)}, tag.Attributes)
+
+		plain, _ := repo.FindById(ctx, 4)
+		assert.Nil(t, plain.Attributes)
+	})
+
+	t.Run("should update metadata", func(t *testing.T) {
+		_, repo := setup()
+		tag, _ := repo.FindById(ctx, 2)
+		tag.Description = "Browser code"
+		tag.Icon = "browser"
+		tag.Attributes = model.TagAttributes{"team": "design"}
+
+		assert.Nil(t, repo.Update(ctx, tag))
+		updated, _ := repo.FindById(ctx, 2)
+		assert.Equal(t, "Browser code", updated.Description)
+		assert.Equal(t, "browser", updated.Icon)
+		assert.Equal(t, model.TagAttributes{"team": "design"}, updated.Attributes)
+	})
+
+	t.Run("should filter on attribute values", func(t *testing.T) {
+		_, repo := setup()
+
+		tests := []struct {
+			attributes map[string][]string
+			expected   []int
+		}{
+			{map[string][]string{"team": {"platform"}}, []int{1, 3}},
+			{map[string][]string{"team": {"platform", "web"}}, []int{1, 2, 3}},
+			{map[string][]string{"team": {"platform"}, "public": {"false"}}, []int{3}},
+			{map[string][]string{"public": {"true"}}, []int{1}},
+			{map[string][]string{"weight": {"2"}}, []int{1}},
+			{map[string][]string{"owner": {"platform"}}, []int{}},
+		}
+		for _, test := range tests {
+			tags, total, err := repo.FindAll(ctx, data.TagQuery{Page: 1, PageSize: 10, Attributes: test.attributes})
+			assert.Nil(t, err)
+			assert.Equal(t, int64(len(test.expected)), total, test.attributes)
+			assert.Equal(t, test.expected, tagIds(tags), test.attributes)
+		}
+	})
+}
+
+func TestTenantIsolation(t *testing.T) {
+	other := tenant.NewContext(context.Background(), "other")
+	setup := func() (*gorm.DB, repository.TagsRepository) {
+		db := setupTestDB()
+		repo := repository.NewTagsRepositoryImpl(db)
+		createMockHierarchy(db)
+		return db, repo
+	}
+
+	t.Run("should stamp new tags with the tenant", func(t *testing.T) {
+		db, repo := setup()
+		assert.Nil(t, repo.Save(other, model.Tags{Name: "golang"}))
+
+		var tag model.Tags
+		db.Where("tenant_id = ?", "other").First(&tag)
+		assert.Equal(t, "golang", tag.Name)
+		assert.NotEqual(t, 2, tag.Id)
+	})
+
+	t.Run("should keep names unique per tenant only", func(t *testing.T) {
+		_, repo := setup()
+		assert.Nil(t, repo.Save(other, model.Tags{Name: "golang"}))
+		assert.ErrorIs(t, repo.Save(other, model.Tags{Name: "GoLang"}), helper.ErrConflict)
+
+		assert.Nil(t, repo.SaveAlias(other, model.TagAlias{TagId: 7, Name: "go"}))
+		assert.Nil(t, repo.SaveAlias(ctx, model.TagAlias{TagId: 2, Name: "go"}))
+		assert.ErrorIs(t, repo.Save(ctx, model.Tags{Name: "go"}), helper.ErrConflict)
+	})
+
+	t.Run("should not read tags of another tenant", func(t *testing.T) {
+		_, repo := setup()
+		assert.Nil(t, repo.Save(other, model.Tags{Name: "rust"}))
+
+		tags, total, err := repo.FindAll(other, data.TagQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []int{7}, tagIds(tags))
+
+		_, err = repo.FindById(other, 2)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		_, err = repo.FindByName(other, "golang")
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+
+		ancestors, err := repo.FindAncestors(other, 4)
+		assert.Nil(t, err)
+		assert.Empty(t, ancestors)
+		tree, _, err := repo.FindTree(other, data.MaxTreeDepth, data.MaxTreeNodes)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{7}, tagIds(tree))
+	})
+
+	t.Run("should not change tags of another tenant", func(t *testing.T) {
+		db, repo := setup()
+		tag, _ := repo.FindById(ctx, 2)
+		tag.Name = "gopher"
+
+		assert.ErrorIs(t, repo.Update(other, tag), helper.ErrPreconditionFailed)
+		assert.ErrorIs(t, repo.Delete(other, 6, 0, data.DeleteReject), helper.ErrNotFound)
+		assert.ErrorIs(t, repo.DeletePermanently(other, 6, 0, data.DeleteReject), helper.ErrNotFound)
+		assert.ErrorIs(t, repo.Merge(other, 1, []int{3}, 0), helper.ErrNotFound)
+		assert.ErrorIs(t, repo.DeleteBatch(other, []int{6}), helper.ErrNotFound)
+		assert.ErrorIs(t, repo.SaveAlias(other, model.TagAlias{TagId: 2, Name: "go"}), helper.ErrNotFound)
+
+		var count int64
+		db.Model(&model.Tags{}).Count(&count)
+		assert.Equal(t, int64(6), count)
+		unchanged, _ := repo.FindById(ctx, 2)
+		assert.Equal(t, "golang", unchanged.Name)
+	})
+
+	t.Run("should keep taggings per tenant", func(t *testing.T) {
+		_, repo := setup()
+		assert.Nil(t, repo.Save(other, model.Tags{Name: "golang"}))
+		assert.Nil(t, attach(repo, 2, "article", "a-1"))
+		assert.Nil(t, repo.Attach(other, model.Tagging{TagId: 7, ResourceType: "article", ResourceId: "a-1"}))
+
+		tags, err := repo.FindByResource(other, "article", "a-1")
+		assert.Nil(t, err)
+		assert.Equal(t, []int{7}, tagIds(tags))
+		assert.ErrorIs(t, repo.Detach(other, model.Tagging{TagId: 2, ResourceType: "article", ResourceId: "a-1"}), helper.ErrNotFound)
+	})
+
+	t.Run("should fail without a tenant", func(t *testing.T) {
+		_, repo := setup()
+
+		_, _, err := repo.FindAll(context.Background(), data.TagQuery{Page: 1, PageSize: 10})
+		assert.ErrorIs(t, err, helper.ErrMissingTenant)
+		assert.ErrorIs(t, repo.Save(context.Background(), model.Tags{Name: "rust"}), helper.ErrMissingTenant)
+		_, err = repo.FindAncestors(context.Background(), 4)
+		assert.ErrorIs(t, err, helper.ErrMissingTenant)
+	})
+}
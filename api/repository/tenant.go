@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"go-gin-project/helper"
+	"go-gin-project/helper/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tenantScope limits a statement to the rows of the tenant in ctx. Without a tenant the statement fails with
+// helper.ErrMissingTenant instead of running across tenants. Raw SQL is not rewritten and has to filter itself.
+func tenantScope(ctx context.Context) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		tenantId, ok := tenant.FromContext(ctx)
+		if !ok {
+			db.AddError(helper.ErrMissingTenant)
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenantId})
+	}
+}
+
+// scoped returns a reusable session bound to ctx in which every statement, transactions and subqueries included,
+// goes through tenantScope. New rows get their tenant from the model hooks.
+func scoped(db *gorm.DB, ctx context.Context) *gorm.DB {
+	return db.WithContext(ctx).Scopes(tenantScope(ctx)).Session(&gorm.Session{})
+}
+
+// withoutTenant returns a session on the same connection or transaction without the tenant scope, for join tables
+// that have no tenant of their own. Callers reach them only through ids already checked against the tenant.
+func withoutTenant(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true})
+}
+
+// statementTenant is the tenant of the session's context, for raw SQL.
+func statementTenant(db *gorm.DB) string {
+	tenantId, _ := tenant.FromContext(db.Statement.Context)
+	return tenantId
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+
+	"gorm.io/gorm"
+)
+
+type TodoRepository interface {
+	Save(ctx context.Context, todo model.Todo) (model.Todo, error)
+	FindAll(ctx context.Context, query data.TodoQuery) ([]model.Todo, int64, error)
+	FindById(ctx context.Context, todoId int) (model.Todo, error)
+	Update(ctx context.Context, todo model.Todo) error
+	Delete(ctx context.Context, todoId int) error
+	FindMissingTags(ctx context.Context, tagIds []int) ([]int, error)
+}
+
+func NewTodoRepositoryImpl(Db *gorm.DB) TodoRepository {
+	return &TodoRepositoryImpl{Db: Db}
+}
+
+type TodoRepositoryImpl struct {
+	Db *gorm.DB
+}
+
+func (t *TodoRepositoryImpl) scoped(ctx context.Context) *gorm.DB {
+	return scoped(t.Db, ctx)
+}
+
+// Save inserts the todo and links it to the tags listed in todo.Tags by id; the tags themselves are not written.
+func (t *TodoRepositoryImpl) Save(ctx context.Context, todo model.Todo) (model.Todo, error) {
+	err := t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags").Create(&todo).Error; err != nil {
+			return err
+		}
+		return linkTodoTags(tx, todo.Id, todo.Tags)
+	})
+	if err != nil {
+		return model.Todo{}, err
+	}
+	return todo, nil
+}
+
+var todoSortColumns = map[string]string{
+	"id":        "id ASC",
+	"-id":       "id DESC",
+	"due_date":  "due_date ASC, id ASC",
+	"-due_date": "due_date DESC, id DESC",
+	"priority":  "priority ASC, id ASC",
+	"-priority": "priority DESC, id DESC",
+}
+
+func (t *TodoRepositoryImpl) FindAll(ctx context.Context, query data.TodoQuery) ([]model.Todo, int64, error) {
+	var total int64
+	result := t.scoped(ctx).Model(&model.Todo{}).Scopes(todoFilters(query)).Count(&total)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	order, ok := todoSortColumns[query.Sort]
+	if !ok {
+		order = todoSortColumns["id"]
+	}
+
+	var todos []model.Todo
+	result = t.scoped(ctx).Scopes(todoFilters(query)).
+		Preload("Tags", orderTagsByName).
+		Order(order).
+		Offset(query.Offset()).
+		Limit(query.PageSize).
+		Find(&todos)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return todos, total, nil
+}
+
+// todoFilters narrows todos by status and tags. With several tag ids a todo matches when it has any of them,
+// or all of them when query.MatchesAllTags is set.
+func todoFilters(query data.TodoQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Status != "" {
+			db = db.Where("status = ?", query.Status)
+		}
+		if len(query.TagIds) == 0 {
+			return db
+		}
+
+		tagged := db.Session(&gorm.Session{NewDB: true}).
+			Model(&model.TodoTag{}).
+			Select("todo_id").
+			Where("tag_id IN ?", query.TagIds)
+		if query.MatchesAllTags() {
+			tagged = tagged.Group("todo_id").Having("COUNT(DISTINCT tag_id) = ?", len(uniqueIds(query.TagIds)))
+		}
+		return db.Where("id IN (?)", tagged)
+	}
+}
+
+func orderTagsByName(db *gorm.DB) *gorm.DB {
+	return db.Order("name ASC, id ASC")
+}
+
+func (t *TodoRepositoryImpl) FindById(ctx context.Context, todoId int) (model.Todo, error) {
+	var todo model.Todo
+	result := t.scoped(ctx).Preload("Tags", orderTagsByName).Where("id = ?", todoId).First(&todo)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return model.Todo{}, helper.ErrNotFound
+	} else if result.Error != nil {
+		return model.Todo{}, result.Error
+	}
+
+	return todo, nil
+}
+
+// Update writes the todo fields and replaces its tags with the ones listed in todo.Tags.
+func (t *TodoRepositoryImpl) Update(ctx context.Context, todo model.Todo) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&todo).Select("Title", "Status", "Priority", "DueDate").Updates(&todo)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helper.ErrNotFound
+		}
+		if err := withoutTenant(tx).Where("todo_id = ?", todo.Id).Delete(&model.TodoTag{}).Error; err != nil {
+			return err
+		}
+		return linkTodoTags(tx, todo.Id, todo.Tags)
+	})
+}
+
+func (t *TodoRepositoryImpl) Delete(ctx context.Context, todoId int) error {
+	return t.scoped(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Todo{}, todoId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return helper.ErrNotFound
+		}
+		return withoutTenant(tx).Where("todo_id = ?", todoId).Delete(&model.TodoTag{}).Error
+	})
+}
+
+// FindMissingTags returns the ids among tagIds that do not name an active tag.
+func (t *TodoRepositoryImpl) FindMissingTags(ctx context.Context, tagIds []int) ([]int, error) {
+	if len(tagIds) == 0 {
+		return nil, nil
+	}
+
+	var existing []int
+	result := t.scoped(ctx).Model(&model.Tags{}).Where("id IN ?", tagIds).Pluck("id", &existing)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	found := map[int]bool{}
+	for _, id := range existing {
+		found[id] = true
+	}
+
+	var missing []int
+	for _, id := range uniqueIds(tagIds) {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+// linkTodoTags links a todo of the tenant to tags the caller has checked with FindMissingTags.
+func linkTodoTags(tx *gorm.DB, todoId int, tags []model.Tags) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	links := make([]model.TodoTag, 0, len(tags))
+	for _, tag := range tags {
+		links = append(links, model.TodoTag{TodoId: todoId, TagId: tag.Id})
+	}
+	return withoutTenant(tx).Create(&links).Error
+}
+
+func uniqueIds(ids []int) []int {
+	seen := map[int]bool{}
+	unique := []int{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
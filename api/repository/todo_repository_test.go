@@ -0,0 +1,187 @@
+package repository_test
+
+import (
+	"context"
+	"go-gin-project/api/repository"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/tenant"
+	"go-gin-project/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func createMockTodos(db *gorm.DB, repo repository.TodoRepository) {
+	createMockData(db)
+	db.Create(&model.Tags{Id: 3, Name: "Tag3"})
+
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	todos := []model.Todo{
+		{Title: "Write docs", Status: data.TodoOpen, Priority: 1, Tags: []model.Tags{{Id: 1}, {Id: 2}}},
+		{Title: "Fix bug", Status: data.TodoDone, Priority: 3, DueDate: &due, Tags: []model.Tags{{Id: 1}}},
+		{Title: "Plan sprint", Status: data.TodoOpen, Priority: 2, Tags: []model.Tags{{Id: 2}, {Id: 3}}},
+	}
+	for _, todo := range todos {
+		repo.Save(ctx, todo)
+	}
+}
+
+func todoIds(todos []model.Todo) []int {
+	ids := []int{}
+	for _, todo := range todos {
+		ids = append(ids, todo.Id)
+	}
+	return ids
+}
+
+func TestTodoRepository(t *testing.T) {
+	t.Run("should save a todo with its tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockData(db)
+
+		saved, err := repo.Save(ctx, model.Todo{Title: "Write docs", Status: data.TodoOpen, Tags: []model.Tags{{Id: 2}, {Id: 1}}})
+		assert.Nil(t, err)
+		assert.NotZero(t, saved.Id)
+
+		todo, err := repo.FindById(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "Write docs", todo.Title)
+		assert.Equal(t, []int{1, 2}, tagIds(todo.Tags))
+		assert.Equal(t, "Tag1", todo.Tags[0].Name)
+
+		var count int64
+		db.Model(&model.Tags{}).Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("should filter todos by any of the tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		todos, total, err := repo.FindAll(ctx, data.TodoQuery{Page: 1, PageSize: 10, TagIds: []int{1, 3}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []int{1, 2, 3}, todoIds(todos))
+
+		todos, total, err = repo.FindAll(ctx, data.TodoQuery{Page: 1, PageSize: 10, TagIds: []int{3}})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []int{3}, todoIds(todos))
+	})
+
+	t.Run("should filter todos by all of the tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		todos, total, err := repo.FindAll(ctx, data.TodoQuery{Page: 1, PageSize: 10, TagIds: []int{1, 2, 2}, TagMatch: "all"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []int{1}, todoIds(todos))
+		assert.Equal(t, []int{1, 2}, tagIds(todos[0].Tags))
+	})
+
+	t.Run("should filter by status and sort by priority", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		todos, total, err := repo.FindAll(ctx, data.TodoQuery{Page: 1, PageSize: 10, Status: data.TodoOpen, Sort: "-priority"})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []int{3, 1}, todoIds(todos))
+	})
+
+	t.Run("should replace fields and tags on update", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		err := repo.Update(ctx, model.Todo{Id: 2, Title: "Fix the bug", Status: data.TodoInProgress, Tags: []model.Tags{{Id: 3}}})
+		assert.Nil(t, err)
+
+		todo, _ := repo.FindById(ctx, 2)
+		assert.Equal(t, "Fix the bug", todo.Title)
+		assert.Equal(t, data.TodoInProgress, todo.Status)
+		assert.Nil(t, todo.DueDate)
+		assert.Equal(t, []int{3}, tagIds(todo.Tags))
+
+		assert.ErrorIs(t, repo.Update(ctx, model.Todo{Id: 99, Title: "Missing"}), helper.ErrNotFound)
+	})
+
+	t.Run("should delete a todo and its tag links", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		assert.Nil(t, repo.Delete(ctx, 1))
+		assert.ErrorIs(t, repo.Delete(ctx, 1), helper.ErrNotFound)
+
+		var count int64
+		db.Model(&model.TodoTag{}).Where("todo_id = ?", 1).Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("should report missing tags", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockData(db)
+		tagsRepo := repository.NewTagsRepositoryImpl(db)
+		assert.Nil(t, tagsRepo.Delete(ctx, 2, 0, data.DeleteReject))
+
+		missing, err := repo.FindMissingTags(ctx, []int{1, 2, 9, 9})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{2, 9}, missing)
+	})
+
+	t.Run("should hide trashed tags and move links on merge", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		tagsRepo := repository.NewTagsRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		assert.Nil(t, tagsRepo.Delete(ctx, 3, 0, data.DeleteReject))
+		todo, _ := repo.FindById(ctx, 3)
+		assert.Equal(t, []int{2}, tagIds(todo.Tags))
+
+		assert.Nil(t, tagsRepo.Restore(ctx, 3))
+		assert.Nil(t, tagsRepo.Merge(ctx, 1, []int{2, 3}, 0))
+		todos, _, err := repo.FindAll(ctx, data.TodoQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		for _, todo := range todos {
+			assert.Equal(t, []int{1}, tagIds(todo.Tags))
+		}
+	})
+}
+
+func TestTodoTenantIsolation(t *testing.T) {
+	other := tenant.NewContext(context.Background(), "other")
+
+	t.Run("should keep todos and their tags per tenant", func(t *testing.T) {
+		db := setupTestDB()
+		repo := repository.NewTodoRepositoryImpl(db)
+		createMockTodos(db, repo)
+
+		todos, total, err := repo.FindAll(other, data.TodoQuery{Page: 1, PageSize: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Empty(t, todos)
+
+		_, err = repo.FindById(other, 1)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		assert.ErrorIs(t, repo.Update(other, model.Todo{Id: 1, Title: "Taken"}), helper.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(other, 1), helper.ErrNotFound)
+
+		missing, err := repo.FindMissingTags(other, []int{1, 2})
+		assert.Nil(t, err)
+		assert.Equal(t, []int{1, 2}, missing)
+
+		todo, _ := repo.FindById(ctx, 1)
+		assert.Equal(t, []int{1, 2}, tagIds(todo.Tags))
+	})
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-gin-project/api/repository"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/tenant"
+	"go-gin-project/model"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// lastUsedResolution limits how often authenticating with a key writes its last-used timestamp.
+const lastUsedResolution = time.Minute
+
+// unknownKeyHash is compared against when no key has the presented prefix, so unknown prefixes cost the same
+// as wrong secrets.
+var unknownKeyHash = auth.HashApiKeySecret("")
+
+type ApiKeysService interface {
+	Create(ctx context.Context, key data.ApiKeyRequest, granted []string) (data.CreatedApiKeyResponse, error)
+	FindAll(ctx context.Context, granted []string) ([]data.ApiKeyResponse, error)
+	Revoke(ctx context.Context, keyId int, granted []string) error
+	Authenticate(ctx context.Context, key string) (data.ApiKeyResponse, error)
+}
+
+func NewApiKeysServiceImpl(apiKeysRepository repository.ApiKeysRepository, validate *validator.Validate) ApiKeysService {
+	return &ApiKeysServiceImpl{
+		ApiKeysRepository: apiKeysRepository,
+		Validate:          validate,
+	}
+}
+
+type ApiKeysServiceImpl struct {
+	ApiKeysRepository repository.ApiKeysRepository
+	Validate          *validator.Validate
+}
+
+// Create generates a key and stores its hash. The plaintext key is part of the response and cannot be recovered later.
+// granted are the scopes of the caller: a key cannot carry scopes the caller lacks, and without auth.ScopeTenantsAny
+// it is bound to the caller's tenant, if any, and cannot name another.
+func (a *ApiKeysServiceImpl) Create(ctx context.Context, key data.ApiKeyRequest, granted []string) (data.CreatedApiKeyResponse, error) {
+	if err := a.Validate.Struct(key); err != nil {
+		return data.CreatedApiKeyResponse{}, helper.ErrFailedValidationWrap(err)
+	}
+	if key.TenantId != "" && !tenant.Valid(key.TenantId) {
+		return data.CreatedApiKeyResponse{}, helper.ErrFailedValidationWrap(fmt.Errorf("invalid tenant %q", key.TenantId))
+	}
+	for _, scope := range key.Scopes {
+		if !auth.KnownScope(scope) {
+			return data.CreatedApiKeyResponse{}, helper.ErrFailedValidationWrap(fmt.Errorf("unknown scope %q", scope))
+		}
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return data.CreatedApiKeyResponse{}, helper.ErrFailedValidationWrap(errors.New("expires_at must be in the future"))
+	}
+	for _, scope := range key.Scopes {
+		if !auth.Grants(granted, scope) {
+			return data.CreatedApiKeyResponse{}, fmt.Errorf("%w: cannot grant scope %q you do not hold", helper.ErrForbidden, scope)
+		}
+	}
+	if callerTenant := keyTenant(ctx, granted); callerTenant != nil {
+		if key.TenantId == "" {
+			key.TenantId = *callerTenant
+		}
+		if key.TenantId != *callerTenant {
+			return data.CreatedApiKeyResponse{}, fmt.Errorf("%w: cannot create a key for tenant %q", helper.ErrForbidden, key.TenantId)
+		}
+	}
+
+	plaintext, prefix, hash, err := auth.NewApiKey()
+	if err != nil {
+		return data.CreatedApiKeyResponse{}, err
+	}
+	saved, err := a.ApiKeysRepository.Save(ctx, model.ApiKey{
+		Name:       key.Name,
+		Prefix:     prefix,
+		SecretHash: hash,
+		Scopes:     uniqueScopes(key.Scopes),
+		TenantId:   key.TenantId,
+		ExpiresAt:  key.ExpiresAt,
+	})
+	if err != nil {
+		return data.CreatedApiKeyResponse{}, err
+	}
+	return data.CreatedApiKeyResponse{ApiKeyResponse: newApiKeyResponse(saved), Key: plaintext}, nil
+}
+
+// FindAll lists the keys of the caller's tenant, or of every tenant for a caller holding auth.ScopeTenantsAny.
+func (a *ApiKeysServiceImpl) FindAll(ctx context.Context, granted []string) ([]data.ApiKeyResponse, error) {
+	result, err := a.ApiKeysRepository.FindAll(ctx, keyTenant(ctx, granted))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []data.ApiKeyResponse{}
+	for _, value := range result {
+		keys = append(keys, newApiKeyResponse(value))
+	}
+	return keys, nil
+}
+
+// Revoke revokes a key of the caller's tenant; keys of other tenants are not found unless the caller holds
+// auth.ScopeTenantsAny.
+func (a *ApiKeysServiceImpl) Revoke(ctx context.Context, keyId int, granted []string) error {
+	return a.ApiKeysRepository.Revoke(ctx, keyId, keyTenant(ctx, granted), time.Now())
+}
+
+// keyTenant is the tenant whose keys the caller administers: its own, or none when it has no tenant. It is nil
+// for a caller holding auth.ScopeTenantsAny, who administers the keys of every tenant.
+func keyTenant(ctx context.Context, granted []string) *string {
+	if auth.Grants(granted, auth.ScopeTenantsAny) {
+		return nil
+	}
+	callerTenant, _ := tenant.FromContext(ctx)
+	return &callerTenant
+}
+
+// Authenticate returns the key matching the plaintext key, or auth.ErrInvalidApiKey when it is unknown, wrong,
+// revoked or expired. The secret is compared in constant time.
+func (a *ApiKeysServiceImpl) Authenticate(ctx context.Context, key string) (data.ApiKeyResponse, error) {
+	prefix, secret, err := auth.ParseApiKey(key)
+	if err != nil {
+		return data.ApiKeyResponse{}, err
+	}
+
+	stored, err := a.ApiKeysRepository.FindByPrefix(ctx, prefix)
+	if errors.Is(err, helper.ErrNotFound) {
+		auth.ApiKeySecretMatches(secret, unknownKeyHash)
+		return data.ApiKeyResponse{}, auth.ErrInvalidApiKey
+	} else if err != nil {
+		return data.ApiKeyResponse{}, err
+	}
+
+	now := time.Now()
+	if !auth.ApiKeySecretMatches(secret, stored.SecretHash) || !stored.Usable(now) {
+		return data.ApiKeyResponse{}, auth.ErrInvalidApiKey
+	}
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) >= lastUsedResolution {
+		if err := a.ApiKeysRepository.MarkUsed(ctx, stored.Id, now); err != nil {
+			return data.ApiKeyResponse{}, err
+		}
+		stored.LastUsedAt = &now
+	}
+	return newApiKeyResponse(stored), nil
+}
+
+func uniqueScopes(scopes []string) model.ApiKeyScopes {
+	unique := model.ApiKeyScopes{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
+
+func newApiKeyResponse(key model.ApiKey) data.ApiKeyResponse {
+	response := data.ApiKeyResponse{
+		Id:        key.Id,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    []string(key.Scopes),
+		TenantId:  key.TenantId,
+		CreatedAt: formatTimestamp(key.CreatedAt),
+	}
+	if response.Scopes == nil {
+		response.Scopes = []string{}
+	}
+	if key.ExpiresAt != nil {
+		response.ExpiresAt = formatTimestamp(*key.ExpiresAt)
+	}
+	if key.LastUsedAt != nil {
+		response.LastUsedAt = formatTimestamp(*key.LastUsedAt)
+	}
+	if key.RevokedAt != nil {
+		response.RevokedAt = formatTimestamp(*key.RevokedAt)
+	}
+	return response
+}
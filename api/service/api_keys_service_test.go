@@ -0,0 +1,234 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/api/service"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/tenant"
+	"go-gin-project/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockApiKeysRepository struct {
+	mock.Mock
+}
+
+func (m *MockApiKeysRepository) Save(_ context.Context, key model.ApiKey) (model.ApiKey, error) {
+	args := m.Called(key)
+	saved, ok := args.Get(0).(model.ApiKey)
+	if !ok {
+		return model.ApiKey{}, errors.New("invalid type assertion for Save")
+	}
+	return saved, args.Error(1)
+}
+
+func (m *MockApiKeysRepository) FindAll(_ context.Context, tenantId *string) ([]model.ApiKey, error) {
+	args := m.Called(tenantId)
+	keys, ok := args.Get(0).([]model.ApiKey)
+	if !ok {
+		return nil, errors.New("invalid type assertion for FindAll")
+	}
+	return keys, args.Error(1)
+}
+
+func (m *MockApiKeysRepository) FindByPrefix(_ context.Context, prefix string) (model.ApiKey, error) {
+	args := m.Called(prefix)
+	key, ok := args.Get(0).(model.ApiKey)
+	if !ok {
+		return model.ApiKey{}, errors.New("invalid type assertion for FindByPrefix")
+	}
+	return key, args.Error(1)
+}
+
+func (m *MockApiKeysRepository) Revoke(_ context.Context, keyId int, tenantId *string, at time.Time) error {
+	args := m.Called(keyId, tenantId, at)
+	return args.Error(0)
+}
+
+func (m *MockApiKeysRepository) MarkUsed(_ context.Context, keyId int, at time.Time) error {
+	args := m.Called(keyId, at)
+	return args.Error(0)
+}
+
+func setupApiKeysTest() (*MockApiKeysRepository, service.ApiKeysService) {
+	mockRepo := new(MockApiKeysRepository)
+	apiKeysService := service.NewApiKeysServiceImpl(mockRepo, validator.New())
+	return mockRepo, apiKeysService
+}
+
+// storedApiKey returns a plaintext key and the row the repository would hold for it.
+func storedApiKey(t *testing.T) (string, model.ApiKey) {
+	key, prefix, hash, err := auth.NewApiKey()
+	assert.Nil(t, err)
+	return key, model.ApiKey{Id: 1, Name: "ci", Prefix: prefix, SecretHash: hash, Scopes: model.ApiKeyScopes{"tags:read"}}
+}
+
+func TestApiKeysService(t *testing.T) {
+	t.Run("should create a key and return the plaintext once", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		var stored model.ApiKey
+		mockRepo.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(model.ApiKey)
+		}).Return(model.ApiKey{Id: 1, Name: "ci"}, nil).Once()
+
+		created, err := apiKeysService.Create(ctx, data.ApiKeyRequest{Name: "ci", Scopes: []string{"tags:read", "tags:read"}, TenantId: "team-a"},
+			[]string{"tags:admin", "api-keys:admin", "tenants:any"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, created.Id)
+		assert.True(t, strings.HasPrefix(created.Key, stored.Prefix+"_"))
+		assert.Equal(t, model.ApiKeyScopes{"tags:read"}, stored.Scopes)
+		assert.Equal(t, "team-a", stored.TenantId)
+		assert.NotContains(t, stored.SecretHash, strings.TrimPrefix(created.Key, stored.Prefix+"_"))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		_, apiKeysService := setupApiKeysTest()
+		past := time.Now().Add(-time.Hour)
+
+		requests := map[string]data.ApiKeyRequest{
+			"missing name":   {},
+			"empty scope":    {Name: "ci", Scopes: []string{""}},
+			"unknown scope":  {Name: "ci", Scopes: []string{"tags:delete"}},
+			"invalid tenant": {Name: "ci", TenantId: "team a"},
+			"expired":        {Name: "ci", ExpiresAt: &past},
+		}
+		for name, request := range requests {
+			_, err := apiKeysService.Create(ctx, request, []string{"tags:admin", "api-keys:admin", "tenants:any"})
+			assert.ErrorIs(t, err, helper.ErrFailedValidation, name)
+		}
+	})
+
+	t.Run("should not grant more than the caller holds", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		teamA := tenant.NewContext(ctx, "team-a")
+		admin := []string{"tags:write", "api-keys:admin"}
+
+		requests := map[string]data.ApiKeyRequest{
+			"broader scope": {Name: "ci", Scopes: []string{"tags:admin"}},
+			"cross-tenant":  {Name: "ci", Scopes: []string{"tenants:any"}},
+			"other tenant":  {Name: "ci", Scopes: []string{"tags:read"}, TenantId: "team-b"},
+		}
+		for name, request := range requests {
+			_, err := apiKeysService.Create(teamA, request, admin)
+			assert.ErrorIs(t, err, helper.ErrForbidden, name)
+		}
+
+		_, err := apiKeysService.Create(ctx, data.ApiKeyRequest{Name: "ci", TenantId: "team-a"}, admin)
+		assert.ErrorIs(t, err, helper.ErrForbidden, "tenant of a caller without one")
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should bind the key to the tenant of the caller", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		var stored model.ApiKey
+		mockRepo.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(model.ApiKey)
+		}).Return(model.ApiKey{Id: 1, Name: "ci"}, nil).Once()
+
+		_, err := apiKeysService.Create(tenant.NewContext(ctx, "team-a"), data.ApiKeyRequest{Name: "ci", Scopes: []string{"tags:read"}},
+			[]string{"tags:write", "api-keys:admin"})
+		assert.Nil(t, err)
+		assert.Equal(t, "team-a", stored.TenantId)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should authenticate a key and record its use", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		key, stored := storedApiKey(t)
+		mockRepo.On("FindByPrefix", stored.Prefix).Return(stored, nil).Once()
+		mockRepo.On("MarkUsed", 1, mock.Anything).Return(nil).Once()
+
+		authenticated, err := apiKeysService.Authenticate(ctx, key)
+		assert.Nil(t, err)
+		assert.Equal(t, stored.Prefix, authenticated.Prefix)
+		assert.Equal(t, []string{"tags:read"}, authenticated.Scopes)
+		assert.NotEmpty(t, authenticated.LastUsedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not record a use seen moments ago", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		key, stored := storedApiKey(t)
+		recent := time.Now().Add(-time.Second)
+		stored.LastUsedAt = &recent
+		mockRepo.On("FindByPrefix", stored.Prefix).Return(stored, nil).Once()
+
+		_, err := apiKeysService.Authenticate(ctx, key)
+		assert.Nil(t, err)
+		mockRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject unknown, wrong, revoked and expired keys", func(t *testing.T) {
+		key, stored := storedApiKey(t)
+		past := time.Now().Add(-time.Minute)
+		revoked, expired := stored, stored
+		revoked.RevokedAt = &past
+		expired.ExpiresAt = &past
+
+		cases := map[string]struct {
+			key    string
+			stored model.ApiKey
+			err    error
+		}{
+			"unknown":   {key, model.ApiKey{}, helper.ErrNotFound},
+			"wrong":     {key + "x", stored, nil},
+			"revoked":   {key, revoked, nil},
+			"expired":   {key, expired, nil},
+			"malformed": {"not-a-key", stored, nil},
+		}
+		for name, c := range cases {
+			mockRepo, apiKeysService := setupApiKeysTest()
+			mockRepo.On("FindByPrefix", stored.Prefix).Return(c.stored, c.err)
+
+			_, err := apiKeysService.Authenticate(ctx, c.key)
+			assert.ErrorIs(t, err, auth.ErrInvalidApiKey, name)
+			mockRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("should list keys without their secrets", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		_, stored := storedApiKey(t)
+		mockRepo.On("FindAll", (*string)(nil)).Return([]model.ApiKey{stored}, nil).Once()
+
+		keys, err := apiKeysService.FindAll(ctx, []string{"api-keys:admin", "tenants:any"})
+		assert.Nil(t, err)
+		assert.Equal(t, []data.ApiKeyResponse{{Id: 1, Name: "ci", Prefix: stored.Prefix, Scopes: []string{"tags:read"}}}, keys)
+	})
+
+	t.Run("should revoke a key", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		mockRepo.On("Revoke", 1, (*string)(nil), mock.Anything).Return(nil).Once()
+		mockRepo.On("Revoke", 2, (*string)(nil), mock.Anything).Return(helper.ErrNotFound).Once()
+
+		anyTenant := []string{"api-keys:admin", "tenants:any"}
+		assert.Nil(t, apiKeysService.Revoke(ctx, 1, anyTenant))
+		assert.ErrorIs(t, apiKeysService.Revoke(ctx, 2, anyTenant), helper.ErrNotFound)
+	})
+
+	t.Run("should limit listing and revoking to the tenant of the caller", func(t *testing.T) {
+		mockRepo, apiKeysService := setupApiKeysTest()
+		teamA, none := "team-a", ""
+		mockRepo.On("FindAll", &teamA).Return([]model.ApiKey{}, nil).Once()
+		mockRepo.On("FindAll", &none).Return([]model.ApiKey{}, nil).Once()
+		mockRepo.On("Revoke", 1, &teamA, mock.Anything).Return(helper.ErrNotFound).Once()
+
+		admin := []string{"api-keys:admin"}
+		_, err := apiKeysService.FindAll(tenant.NewContext(ctx, "team-a"), admin)
+		assert.Nil(t, err)
+		_, err = apiKeysService.FindAll(ctx, admin)
+		assert.Nil(t, err)
+		assert.ErrorIs(t, apiKeysService.Revoke(tenant.NewContext(ctx, "team-a"), 1, admin), helper.ErrNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
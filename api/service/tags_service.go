@@ -1,84 +1,295 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"go-gin-project/api/repository"
+	"go-gin-project/config"
 	"go-gin-project/data"
 	"go-gin-project/helper"
+	"go-gin-project/helper/cursor"
+	"go-gin-project/helper/logging"
 	"go-gin-project/model"
+	"slices"
+	"strings"
+	"time"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/go-playground/validator/v10"
 )
 
 type TagsService interface {
-	Create(tag data.TagRequest) error
-	FindAll() ([]data.TagResponse, error)
-	FindById(tagId string) (data.TagResponse, error)
-	Update(tagId string, tag data.TagRequest) error
-	Delete(tagId int) error
+	Create(ctx context.Context, tag data.TagRequest) error
+	FindAll(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error)
+	FindAllByCursor(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.CursorMeta, error)
+	FindById(ctx context.Context, tagId int) (data.TagResponse, error)
+	FindByName(ctx context.Context, name string) (data.TagResponse, error)
+	FindChildren(ctx context.Context, tagId int) ([]data.TagResponse, error)
+	FindAncestors(ctx context.Context, tagId int) ([]data.TagResponse, error)
+	FindTree(ctx context.Context, query data.TagTreeQuery) ([]data.TagTreeNode, data.TagTreeMeta, error)
+	Update(ctx context.Context, tagId int, tag data.TagRequest, version int) error
+	Patch(ctx context.Context, tagId int, contentType string, patch []byte, version int) error
+	Move(ctx context.Context, tagId int, parentId *int, version int) error
+	Delete(ctx context.Context, tagId int, version int, strategy string) error
+	DeletePermanently(ctx context.Context, tagId int, version int, strategy string) error
+	FindTrash(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error)
+	Restore(ctx context.Context, tagId int) error
+	PurgeTrash(ctx context.Context) (data.PurgeResponse, error)
+	BulkCreate(ctx context.Context, tags []data.TagRequest, partial bool) ([]data.BulkResult, error)
+	BulkUpdate(ctx context.Context, tags []data.BulkTagUpdateRequest, partial bool) ([]data.BulkResult, error)
+	BulkDelete(ctx context.Context, tagIds []int, partial bool) ([]data.BulkResult, error)
+	AddAlias(ctx context.Context, tagId int, alias data.AliasRequest) error
+	DeleteAlias(ctx context.Context, tagId int, name string) error
+	Merge(ctx context.Context, tagId int, merge data.MergeTagRequest, version int) error
+	Attach(ctx context.Context, tagId int, resource data.ResourceRef) error
+	Detach(ctx context.Context, tagId int, resource data.ResourceRef) error
+	FindByResource(ctx context.Context, resource data.ResourceRef) ([]data.TagResponse, error)
+	FindResources(ctx context.Context, tagId int, query data.TaggingQuery) ([]data.ResourceResponse, data.PageMeta, error)
 }
 
-func NewTagsServiceImpl(tagsRepository repository.TagsRepository, validate *validator.Validate) TagsService {
+func NewTagsServiceImpl(tagsRepository repository.TagsRepository, validate *validator.Validate, cursorSigner *cursor.Signer, trashRetention config.TrashRetention) TagsService {
 	return &TagsServiceImpl{
 		TagsRepository: tagsRepository,
 		Validate:       validate,
+		CursorSigner:   cursorSigner,
+		TrashRetention: time.Duration(trashRetention),
 	}
 }
 
 type TagsServiceImpl struct {
 	TagsRepository repository.TagsRepository
 	Validate       *validator.Validate
+	CursorSigner   *cursor.Signer
+	TrashRetention time.Duration
 }
 
-func (t *TagsServiceImpl) Create(tag data.TagRequest) error {
+func (t *TagsServiceImpl) Create(ctx context.Context, tag data.TagRequest) error {
 	err := t.Validate.Struct(tag)
 	if err != nil {
 		return helper.ErrFailedValidationWrap(err)
 	}
-	tagModel := model.Tags{
-		Name: tag.Name,
+	if err := t.checkParent(ctx, 0, tag.ParentId); err != nil {
+		return err
 	}
-	return t.TagsRepository.Save(tagModel)
+	tagModel := model.Tags{}
+	applyTagRequest(&tagModel, tag)
+	return t.TagsRepository.Save(ctx, tagModel)
 }
 
-func (t *TagsServiceImpl) FindAll() ([]data.TagResponse, error) {
-	result, err := t.TagsRepository.FindAll()
+func (t *TagsServiceImpl) FindAll(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error) {
+	err := t.Validate.Struct(query)
 	if err != nil {
-		return nil, err
+		return nil, data.PageMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+
+	result, total, err := t.TagsRepository.FindAll(ctx, query)
+	if err != nil {
+		return nil, data.PageMeta{}, err
 	}
 
-	var tags []data.TagResponse
+	tags := []data.TagResponse{}
 	for _, value := range result {
-		tag := data.TagResponse{
-			Id:   value.Id,
-			Name: value.Name,
+		tags = append(tags, newTagResponse(value))
+	}
+
+	return tags, data.NewPageMeta(query.Page, query.PageSize, total), nil
+}
+
+func (t *TagsServiceImpl) FindAllByCursor(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.CursorMeta, error) {
+	err := t.Validate.Struct(query)
+	if err != nil {
+		return nil, data.CursorMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+	if query.Sort == "" {
+		query.Sort = "id"
+	}
+
+	filters := cursorFilters(query)
+	var position *data.TagCursor
+	if query.Cursor != "" {
+		position = &data.TagCursor{}
+		if err := t.CursorSigner.Decode(query.Cursor, position); err != nil {
+			return nil, data.CursorMeta{}, helper.ErrFailedValidationWrap(err)
+		}
+		if position.Sort != query.Sort || position.Filters != filters {
+			return nil, data.CursorMeta{}, helper.ErrFailedValidationWrap(cursor.ErrInvalidCursor)
 		}
-		tags = append(tags, tag)
 	}
 
-	return tags, nil
+	result, hasMore, err := t.TagsRepository.FindAllByCursor(ctx, query, position)
+	if err != nil {
+		return nil, data.CursorMeta{}, err
+	}
+
+	tags := []data.TagResponse{}
+	for _, value := range result {
+		tags = append(tags, newTagResponse(value))
+	}
+
+	meta := data.CursorMeta{PageSize: query.PageSize}
+	if len(result) == 0 {
+		return tags, meta, nil
+	}
+
+	backward := position != nil && position.Backward
+	if hasMore || backward {
+		last := result[len(result)-1]
+		meta.NextCursor, err = t.CursorSigner.Encode(data.TagCursor{Id: last.Id, Name: last.Name, Sort: query.Sort, Filters: filters})
+		if err != nil {
+			return nil, data.CursorMeta{}, err
+		}
+	}
+	if (hasMore && backward) || (position != nil && !backward) {
+		first := result[0]
+		meta.PrevCursor, err = t.CursorSigner.Encode(data.TagCursor{Id: first.Id, Name: first.Name, Sort: query.Sort, Filters: filters, Backward: true})
+		if err != nil {
+			return nil, data.CursorMeta{}, err
+		}
+	}
+
+	return tags, meta, nil
 }
 
-func (t *TagsServiceImpl) FindById(tagId string) (data.TagResponse, error) {
-	tagData, err := t.TagsRepository.FindById(tagId)
+// cursorFilters digests the filters of a cursor listing, so a cursor cannot carry over to a differently filtered
+// one. It is empty for an unfiltered listing.
+func cursorFilters(query data.TagQuery) string {
+	if query.NameContains == "" && query.NamePrefix == "" && query.CreatedAfter.IsZero() && query.UpdatedSince.IsZero() &&
+		len(query.Attributes) == 0 {
+		return ""
+	}
+
+	attributes := make(map[string][]string, len(query.Attributes))
+	for key, values := range query.Attributes {
+		values = slices.Clone(values)
+		slices.Sort(values)
+		attributes[key] = values
+	}
+	// encoding/json sorts map keys, which keeps the digest stable.
+	payload, err := json.Marshal([]interface{}{
+		query.NameContains,
+		query.NamePrefix,
+		query.CreatedAfter.UTC().Format(time.RFC3339Nano),
+		query.UpdatedSince.UTC().Format(time.RFC3339Nano),
+		attributes,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func (t *TagsServiceImpl) FindById(ctx context.Context, tagId int) (data.TagResponse, error) {
+	tagData, err := t.TagsRepository.FindById(ctx, tagId)
 	if err != nil {
 		return data.TagResponse{}, err
 	}
 
-	tagResponse := data.TagResponse{
-		Id:   tagData.Id,
-		Name: tagData.Name,
+	return newTagResponse(tagData), nil
+}
+
+// FindByName resolves a tag name or alias to its canonical tag.
+func (t *TagsServiceImpl) FindByName(ctx context.Context, name string) (data.TagResponse, error) {
+	tagData, err := t.TagsRepository.FindByName(ctx, name)
+	if err != nil {
+		return data.TagResponse{}, err
 	}
 
-	return tagResponse, nil
+	return newTagResponse(tagData), nil
 }
 
-func (t *TagsServiceImpl) Update(tagId string, tag data.TagRequest) error {
+func (t *TagsServiceImpl) FindChildren(ctx context.Context, tagId int) ([]data.TagResponse, error) {
+	if _, err := t.TagsRepository.FindById(ctx, tagId); err != nil {
+		return nil, err
+	}
+
+	result, err := t.TagsRepository.FindChildren(ctx, tagId)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []data.TagResponse{}
+	for _, value := range result {
+		tags = append(tags, newTagResponse(value))
+	}
+	return tags, nil
+}
+
+// FindAncestors returns the parents above a tag, root first.
+func (t *TagsServiceImpl) FindAncestors(ctx context.Context, tagId int) ([]data.TagResponse, error) {
+	if _, err := t.TagsRepository.FindById(ctx, tagId); err != nil {
+		return nil, err
+	}
+
+	result, err := t.TagsRepository.FindAncestors(ctx, tagId)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []data.TagResponse{}
+	for _, value := range result {
+		tags = append(tags, newTagResponse(value))
+	}
+	return tags, nil
+}
+
+// FindTree nests the active tags below their parents, down to query.MaxDepth levels and up to data.MaxTreeNodes
+// tags. Tags whose parent is in the trash are listed as roots.
+func (t *TagsServiceImpl) FindTree(ctx context.Context, query data.TagTreeQuery) ([]data.TagTreeNode, data.TagTreeMeta, error) {
+	if err := t.Validate.Struct(query); err != nil {
+		return nil, data.TagTreeMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+
+	result, truncated, err := t.TagsRepository.FindTree(ctx, query.MaxDepth, data.MaxTreeNodes)
+	if err != nil {
+		return nil, data.TagTreeMeta{}, err
+	}
+	meta := data.TagTreeMeta{MaxDepth: query.MaxDepth, Nodes: len(result), Truncated: truncated}
+
+	active := map[int]bool{}
+	for _, tag := range result {
+		active[tag.Id] = true
+	}
+	roots := []model.Tags{}
+	children := map[int][]model.Tags{}
+	for _, tag := range result {
+		if tag.ParentId != nil && active[*tag.ParentId] {
+			children[*tag.ParentId] = append(children[*tag.ParentId], tag)
+		} else {
+			roots = append(roots, tag)
+		}
+	}
+	return newTagTree(roots, children), meta, nil
+}
+
+func newTagTree(tags []model.Tags, children map[int][]model.Tags) []data.TagTreeNode {
+	nodes := []data.TagTreeNode{}
+	for _, tag := range tags {
+		nodes = append(nodes, data.TagTreeNode{
+			TagResponse: newTagResponse(tag),
+			Children:    newTagTree(children[tag.Id], children),
+		})
+	}
+	return nodes
+}
+
+// Update applies the request to the current tag. A non-zero version must match the stored one,
+// otherwise helper.ErrPreconditionFailed is returned.
+func (t *TagsServiceImpl) Update(ctx context.Context, tagId int, tag data.TagRequest, version int) error {
 	err := t.Validate.Struct(tag)
 	if err != nil {
 		return helper.ErrFailedValidationWrap(err)
 	}
 
-	tagData, err := t.TagsRepository.FindById(tagId)
+	tagData, err := t.TagsRepository.FindById(ctx, tagId)
 	if err != nil {
 		return err
 	}
@@ -86,11 +297,565 @@ func (t *TagsServiceImpl) Update(tagId string, tag data.TagRequest) error {
 	if tagData.Id == 0 {
 		return helper.ErrNotFound
 	}
+	if version != 0 && tagData.Version != version {
+		return helper.ErrPreconditionFailed
+	}
+	if err := t.checkParent(ctx, tagData.Id, tag.ParentId); err != nil {
+		return err
+	}
 
-	tagData.Name = tag.Name
-	return t.TagsRepository.Update(tagData)
+	applyTagRequest(&tagData, tag)
+	return t.TagsRepository.Update(ctx, tagData)
 }
 
-func (t *TagsServiceImpl) Delete(tagId int) error {
-	return t.TagsRepository.Delete(tagId)
+// Patch applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document to the tag's current request
+// representation, validates the result like a full update and persists it.
+func (t *TagsServiceImpl) Patch(ctx context.Context, tagId int, contentType string, patch []byte, version int) error {
+	tagData, err := t.TagsRepository.FindById(ctx, tagId)
+	if err != nil {
+		return err
+	}
+	if version != 0 && tagData.Version != version {
+		return helper.ErrPreconditionFailed
+	}
+
+	current, err := json.Marshal(newTagPatchDocument(tagData))
+	if err != nil {
+		return err
+	}
+	patched, err := applyPatch(contentType, current, patch)
+	if err != nil {
+		return helper.ErrInvalidPatchWrap(err)
+	}
+
+	tag := data.TagRequest{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tag); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	if err := t.Validate.Struct(tag); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	if err := t.checkParent(ctx, tagData.Id, tag.ParentId); err != nil {
+		return err
+	}
+
+	applyTagRequest(&tagData, tag)
+	return t.TagsRepository.Update(ctx, tagData)
+}
+
+func applyPatch(contentType string, document []byte, patch []byte) ([]byte, error) {
+	switch contentType {
+	case data.MergePatchContentType:
+		return jsonpatch.MergePatch(document, patch)
+	case data.JSONPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		return operations.Apply(document)
+	default:
+		return nil, errors.New("unsupported patch content type " + contentType)
+	}
+}
+
+// Move hangs the tag below parentId, or makes it a root when parentId is nil.
+func (t *TagsServiceImpl) Move(ctx context.Context, tagId int, parentId *int, version int) error {
+	tagData, err := t.TagsRepository.FindById(ctx, tagId)
+	if err != nil {
+		return err
+	}
+	if version != 0 && tagData.Version != version {
+		return helper.ErrPreconditionFailed
+	}
+	if err := t.checkParent(ctx, tagData.Id, parentId); err != nil {
+		return err
+	}
+
+	tagData.ParentId = parentId
+	return t.TagsRepository.Update(ctx, tagData)
+}
+
+var errTagCycle = errors.New("a tag cannot be placed below itself or one of its descendants")
+
+// checkParent makes sure parentId names an existing tag and that placing tagId below it does not create a cycle.
+// tagId is 0 for a tag that does not exist yet.
+func (t *TagsServiceImpl) checkParent(ctx context.Context, tagId int, parentId *int) error {
+	if parentId == nil {
+		return nil
+	}
+	if *parentId == tagId {
+		return helper.ErrFailedValidationWrap(errTagCycle)
+	}
+	if _, err := t.TagsRepository.FindById(ctx, *parentId); err != nil {
+		if errors.Is(err, helper.ErrNotFound) {
+			return helper.ErrFailedValidationWrap(fmt.Errorf("parent tag %d does not exist", *parentId))
+		}
+		return err
+	}
+	if tagId == 0 {
+		return nil
+	}
+
+	ancestors, err := t.TagsRepository.FindAncestors(ctx, *parentId)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.Id == tagId {
+			return helper.ErrFailedValidationWrap(errTagCycle)
+		}
+	}
+	return nil
+}
+
+func (t *TagsServiceImpl) Delete(ctx context.Context, tagId int, version int, strategy string) error {
+	if err := validateDeleteStrategy(strategy); err != nil {
+		return err
+	}
+	if err := t.TagsRepository.Delete(ctx, tagId, version, strategy); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "tag moved to trash", "tag_id", tagId, "strategy", strategy)
+	return nil
+}
+
+func (t *TagsServiceImpl) DeletePermanently(ctx context.Context, tagId int, version int, strategy string) error {
+	if err := validateDeleteStrategy(strategy); err != nil {
+		return err
+	}
+	if err := t.TagsRepository.DeletePermanently(ctx, tagId, version, strategy); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "tag deleted permanently", "tag_id", tagId, "strategy", strategy)
+	return nil
+}
+
+func validateDeleteStrategy(strategy string) error {
+	switch strategy {
+	case data.DeleteReject, data.DeleteCascade, data.DeleteReparent:
+		return nil
+	}
+	return helper.ErrFailedValidationWrap(fmt.Errorf("unknown delete strategy %q", strategy))
+}
+
+func (t *TagsServiceImpl) FindTrash(ctx context.Context, query data.TagQuery) ([]data.TagResponse, data.PageMeta, error) {
+	err := t.Validate.Struct(query)
+	if err != nil {
+		return nil, data.PageMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+
+	result, total, err := t.TagsRepository.FindTrash(ctx, query)
+	if err != nil {
+		return nil, data.PageMeta{}, err
+	}
+
+	tags := []data.TagResponse{}
+	for _, value := range result {
+		tags = append(tags, newTagResponse(value))
+	}
+
+	return tags, data.NewPageMeta(query.Page, query.PageSize, total), nil
+}
+
+func (t *TagsServiceImpl) Restore(ctx context.Context, tagId int) error {
+	return t.TagsRepository.Restore(ctx, tagId)
+}
+
+// PurgeTrash permanently drops tags that have been in the trash for longer than the configured retention.
+func (t *TagsServiceImpl) PurgeTrash(ctx context.Context) (data.PurgeResponse, error) {
+	purged, err := t.TagsRepository.PurgeTrash(ctx, time.Now().Add(-t.TrashRetention))
+	if err != nil {
+		return data.PurgeResponse{}, err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "trash purged", "purged", purged, "retention", t.TrashRetention)
+	return data.PurgeResponse{Purged: purged}, nil
+}
+
+// BulkCreate creates all tags in one transaction. In partial mode every item is created on its own and failures
+// are only reported in the results. Outside partial mode a returned error means nothing was written.
+func (t *TagsServiceImpl) BulkCreate(ctx context.Context, tags []data.TagRequest, partial bool) ([]data.BulkResult, error) {
+	if err := validateBulkSize(len(tags)); err != nil {
+		return nil, err
+	}
+
+	batch := newBulkBatch(len(tags))
+	models := []model.Tags{}
+	seen := map[string]int{}
+	for index, tag := range tags {
+		if err := t.Validate.Struct(tag); err != nil {
+			batch.errs[index] = helper.ErrFailedValidationWrap(err)
+			continue
+		}
+		name := model.NormalizeTagName(tag.Name)
+		if first, ok := seen[name]; ok {
+			batch.errs[index] = fmt.Errorf("%w: same name as item %d", helper.ErrConflict, first)
+			continue
+		}
+		if err := t.checkParent(ctx, 0, tag.ParentId); err != nil {
+			batch.errs[index] = err
+			continue
+		}
+		seen[name] = index
+		tagModel := model.Tags{}
+		applyTagRequest(&tagModel, tag)
+		models = append(models, tagModel)
+		batch.pending = append(batch.pending, index)
+	}
+
+	if !partial {
+		if err := batch.firstError(); err != nil {
+			return batch.results(), err
+		}
+		saved, err := t.TagsRepository.SaveBatch(ctx, models)
+		if err != nil {
+			return batch.rollback(err), err
+		}
+		for i, tag := range saved {
+			batch.ids[batch.pending[i]] = tag.Id
+		}
+		return batch.results(), nil
+	}
+
+	for i, tag := range models {
+		saved, err := t.TagsRepository.SaveBatch(ctx, []model.Tags{tag})
+		if err != nil {
+			batch.errs[batch.pending[i]] = unwrapBatchItem(err)
+			continue
+		}
+		batch.ids[batch.pending[i]] = saved[0].Id
+	}
+	return batch.results(), nil
+}
+
+// BulkUpdate updates tags by id like Update does, honouring a per-item version when one is given.
+func (t *TagsServiceImpl) BulkUpdate(ctx context.Context, tags []data.BulkTagUpdateRequest, partial bool) ([]data.BulkResult, error) {
+	if err := validateBulkSize(len(tags)); err != nil {
+		return nil, err
+	}
+
+	batch := newBulkBatch(len(tags))
+	models := []model.Tags{}
+	for index, tag := range tags {
+		batch.ids[index] = tag.Id
+		if err := t.Validate.Struct(tag); err != nil {
+			batch.errs[index] = helper.ErrFailedValidationWrap(err)
+			continue
+		}
+		if err := t.checkParent(ctx, tag.Id, tag.ParentId); err != nil {
+			batch.errs[index] = err
+			continue
+		}
+		tagModel := model.Tags{Id: tag.Id, Version: tag.Version}
+		applyTagRequest(&tagModel, tag.TagRequest)
+		models = append(models, tagModel)
+		batch.pending = append(batch.pending, index)
+	}
+
+	if !partial {
+		if err := batch.firstError(); err != nil {
+			return batch.results(), err
+		}
+		if err := t.TagsRepository.UpdateBatch(ctx, models); err != nil {
+			return batch.rollback(err), err
+		}
+		return batch.results(), nil
+	}
+
+	for i, tag := range models {
+		if err := t.TagsRepository.UpdateBatch(ctx, []model.Tags{tag}); err != nil {
+			batch.errs[batch.pending[i]] = unwrapBatchItem(err)
+		}
+	}
+	return batch.results(), nil
+}
+
+// BulkDelete moves tags to the trash by id.
+func (t *TagsServiceImpl) BulkDelete(ctx context.Context, tagIds []int, partial bool) ([]data.BulkResult, error) {
+	if err := validateBulkSize(len(tagIds)); err != nil {
+		return nil, err
+	}
+
+	batch := newBulkBatch(len(tagIds))
+	for index, id := range tagIds {
+		batch.ids[index] = id
+		batch.pending = append(batch.pending, index)
+	}
+
+	if !partial {
+		if err := t.TagsRepository.DeleteBatch(ctx, tagIds); err != nil {
+			return batch.rollback(err), err
+		}
+		return batch.results(), nil
+	}
+
+	for index, id := range tagIds {
+		if err := t.TagsRepository.DeleteBatch(ctx, []int{id}); err != nil {
+			batch.errs[index] = unwrapBatchItem(err)
+		}
+	}
+	return batch.results(), nil
+}
+
+func (t *TagsServiceImpl) AddAlias(ctx context.Context, tagId int, alias data.AliasRequest) error {
+	if err := t.Validate.Struct(alias); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	if _, err := t.TagsRepository.FindById(ctx, tagId); err != nil {
+		return err
+	}
+	return t.TagsRepository.SaveAlias(ctx, model.TagAlias{TagId: tagId, Name: alias.Name})
+}
+
+func (t *TagsServiceImpl) DeleteAlias(ctx context.Context, tagId int, name string) error {
+	return t.TagsRepository.DeleteAlias(ctx, tagId, name)
+}
+
+// Merge folds the source tags into the tag. Sources may not include the tag itself or one of its ancestors,
+// whose children would otherwise end up below their own descendant.
+func (t *TagsServiceImpl) Merge(ctx context.Context, tagId int, merge data.MergeTagRequest, version int) error {
+	if err := t.Validate.Struct(merge); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	sources := map[int]bool{}
+	for _, id := range merge.SourceIds {
+		if id == tagId {
+			return helper.ErrFailedValidationWrap(errors.New("a tag cannot be merged into itself"))
+		}
+		if sources[id] {
+			return helper.ErrFailedValidationWrap(fmt.Errorf("tag %d is listed more than once", id))
+		}
+		sources[id] = true
+	}
+
+	tagData, err := t.TagsRepository.FindById(ctx, tagId)
+	if err != nil {
+		return err
+	}
+	if version != 0 && tagData.Version != version {
+		return helper.ErrPreconditionFailed
+	}
+	ancestors, err := t.TagsRepository.FindAncestors(ctx, tagId)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if sources[ancestor.Id] {
+			return helper.ErrFailedValidationWrap(fmt.Errorf("tag %d is an ancestor of the merge target", ancestor.Id))
+		}
+	}
+
+	if err := t.TagsRepository.Merge(ctx, tagId, merge.SourceIds, version); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).InfoContext(ctx, "tags merged", "tag_id", tagId, "source_ids", merge.SourceIds)
+	return nil
+}
+
+// Attach tags a resource with an active tag.
+func (t *TagsServiceImpl) Attach(ctx context.Context, tagId int, resource data.ResourceRef) error {
+	if err := t.Validate.Struct(resource); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	if _, err := t.TagsRepository.FindById(ctx, tagId); err != nil {
+		return err
+	}
+	return t.TagsRepository.Attach(ctx, newTagging(tagId, resource))
+}
+
+func (t *TagsServiceImpl) Detach(ctx context.Context, tagId int, resource data.ResourceRef) error {
+	if err := t.Validate.Struct(resource); err != nil {
+		return helper.ErrFailedValidationWrap(err)
+	}
+	return t.TagsRepository.Detach(ctx, newTagging(tagId, resource))
+}
+
+func (t *TagsServiceImpl) FindByResource(ctx context.Context, resource data.ResourceRef) ([]data.TagResponse, error) {
+	if err := t.Validate.Struct(resource); err != nil {
+		return nil, helper.ErrFailedValidationWrap(err)
+	}
+
+	result, err := t.TagsRepository.FindByResource(ctx, resource.ResourceType, resource.ResourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []data.TagResponse{}
+	for _, value := range result {
+		tags = append(tags, newTagResponse(value))
+	}
+	return tags, nil
+}
+
+func (t *TagsServiceImpl) FindResources(ctx context.Context, tagId int, query data.TaggingQuery) ([]data.ResourceResponse, data.PageMeta, error) {
+	if err := t.Validate.Struct(query); err != nil {
+		return nil, data.PageMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+	if _, err := t.TagsRepository.FindById(ctx, tagId); err != nil {
+		return nil, data.PageMeta{}, err
+	}
+
+	result, total, err := t.TagsRepository.FindResources(ctx, tagId, query)
+	if err != nil {
+		return nil, data.PageMeta{}, err
+	}
+
+	resources := []data.ResourceResponse{}
+	for _, value := range result {
+		resources = append(resources, data.ResourceResponse{
+			ResourceType: value.ResourceType,
+			ResourceId:   value.ResourceId,
+			TaggedAt:     formatTimestamp(value.CreatedAt),
+		})
+	}
+	return resources, data.NewPageMeta(query.Page, query.PageSize, total), nil
+}
+
+func newTagging(tagId int, resource data.ResourceRef) model.Tagging {
+	return model.Tagging{TagId: tagId, ResourceType: resource.ResourceType, ResourceId: resource.ResourceId}
+}
+
+func validateBulkSize(size int) error {
+	if size == 0 || size > data.MaxBulkItems {
+		return helper.ErrFailedValidationWrap(fmt.Errorf("expected between 1 and %d items, got %d", data.MaxBulkItems, size))
+	}
+	return nil
+}
+
+// bulkBatch tracks the outcome of every item of a bulk request by its index in the request.
+// pending lists the indexes, in order, of the items that were handed to the repository.
+type bulkBatch struct {
+	ids     []int
+	errs    []error
+	pending []int
+}
+
+func newBulkBatch(size int) *bulkBatch {
+	return &bulkBatch{
+		ids:  make([]int, size),
+		errs: make([]error, size),
+	}
+}
+
+func (b *bulkBatch) firstError() error {
+	for index, err := range b.errs {
+		if err != nil {
+			return &helper.BatchItemError{Index: index, Err: err}
+		}
+	}
+	return nil
+}
+
+// rollback records a failed transaction: the items named by a *helper.BatchItemError or helper.BatchItemErrors
+// get their own errors and every other item is reported as rolled back.
+func (b *bulkBatch) rollback(err error) []data.BulkResult {
+	items := batchItems(err)
+	cause := err
+	if len(items) > 0 {
+		cause = items[0].Err
+	}
+	for index := range b.errs {
+		b.errs[index] = fmt.Errorf("rolled back: %w", cause)
+	}
+	for _, itemErr := range items {
+		b.errs[b.pending[itemErr.Index]] = itemErr.Err
+	}
+	return b.results()
+}
+
+func (b *bulkBatch) results() []data.BulkResult {
+	results := make([]data.BulkResult, len(b.errs))
+	for index, err := range b.errs {
+		results[index] = data.BulkResult{Index: index, Id: b.ids[index], Success: err == nil}
+		if err != nil {
+			results[index].Error = err.Error()
+		}
+	}
+	return results
+}
+
+func batchItems(err error) []*helper.BatchItemError {
+	var itemErrs helper.BatchItemErrors
+	if errors.As(err, &itemErrs) {
+		return itemErrs
+	}
+	var itemErr *helper.BatchItemError
+	if errors.As(err, &itemErr) {
+		return []*helper.BatchItemError{itemErr}
+	}
+	return nil
+}
+
+func unwrapBatchItem(err error) error {
+	var itemErr *helper.BatchItemError
+	if errors.As(err, &itemErr) {
+		return itemErr.Err
+	}
+	return err
+}
+
+// applyTagRequest copies the writable fields of the request onto the tag. Colors are stored in lower case.
+func applyTagRequest(tagModel *model.Tags, tag data.TagRequest) {
+	tagModel.Name = tag.Name
+	tagModel.ParentId = tag.ParentId
+	tagModel.Description = tag.Description
+	tagModel.Color = strings.ToLower(tag.Color)
+	tagModel.Icon = tag.Icon
+	tagModel.Attributes = model.TagAttributes(tag.Attributes)
+}
+
+// newTagPatchDocument renders the tag as stored for patches to apply to; decoded as a data.TagRequest it updates
+// the tag to itself.
+func newTagPatchDocument(tag model.Tags) data.TagPatchDocument {
+	document := data.TagPatchDocument{
+		Name:        tag.Name,
+		ParentId:    tag.ParentId,
+		Description: nullableString(tag.Description),
+		Color:       nullableString(tag.Color),
+		Icon:        nullableString(tag.Icon),
+	}
+	if len(tag.Attributes) > 0 {
+		document.Attributes = tag.Attributes
+	}
+	return document
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func newTagResponse(tag model.Tags) data.TagResponse {
+	response := data.TagResponse{
+		Id:          tag.Id,
+		Name:        tag.Name,
+		ParentId:    tag.ParentId,
+		Description: tag.Description,
+		Color:       tag.Color,
+		Icon:        tag.Icon,
+		Attributes:  tag.Attributes,
+		Version:     tag.Version,
+		CreatedAt:   formatTimestamp(tag.CreatedAt),
+		UpdatedAt:   formatTimestamp(tag.UpdatedAt),
+	}
+	if tag.DeletedAt.Valid {
+		response.DeletedAt = formatTimestamp(tag.DeletedAt.Time)
+	}
+	for _, alias := range tag.Aliases {
+		response.Aliases = append(response.Aliases, alias.Name)
+	}
+	return response
+}
+
+// formatTimestamp renders times as RFC 3339 in UTC, whatever zone the database session uses.
+func formatTimestamp(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
 }
@@ -1,36 +1,55 @@
 package service_test
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"go-gin-project/api/service"
+	"go-gin-project/config"
 	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/helper/cursor"
+	"go-gin-project/helper/logging"
 	"go-gin-project/model"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockTagsRepository struct {
 	mock.Mock
 }
 
-func (m *MockTagsRepository) Save(tag model.Tags) error {
+func (m *MockTagsRepository) Save(_ context.Context, tag model.Tags) error {
 	args := m.Called(tag)
 	return args.Error(0)
 }
 
-func (m *MockTagsRepository) FindAll() ([]model.Tags, error) {
-	args := m.Called()
+func (m *MockTagsRepository) FindAll(_ context.Context, query data.TagQuery) ([]model.Tags, int64, error) {
+	args := m.Called(query)
 	tags, ok := args.Get(0).([]model.Tags)
 	if !ok {
-		return nil, errors.New("invalid type assertion for FindAll")
+		return nil, 0, errors.New("invalid type assertion for FindAll")
 	}
-	return tags, args.Error(1)
+	return tags, args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTagsRepository) FindById(tagId string) (model.Tags, error) {
+func (m *MockTagsRepository) FindAllByCursor(_ context.Context, query data.TagQuery, position *data.TagCursor) ([]model.Tags, bool, error) {
+	args := m.Called(query, position)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, false, errors.New("invalid type assertion for FindAllByCursor")
+	}
+	return tags, args.Bool(1), args.Error(2)
+}
+
+func (m *MockTagsRepository) FindById(_ context.Context, tagId int) (model.Tags, error) {
 	args := m.Called(tagId)
 	tag, ok := args.Get(0).(model.Tags)
 	if !ok {
@@ -39,20 +58,148 @@ func (m *MockTagsRepository) FindById(tagId string) (model.Tags, error) {
 	return tag, args.Error(1)
 }
 
-func (m *MockTagsRepository) Update(tag model.Tags) error {
+func (m *MockTagsRepository) FindByName(_ context.Context, name string) (model.Tags, error) {
+	args := m.Called(name)
+	tag, ok := args.Get(0).(model.Tags)
+	if !ok {
+		return model.Tags{}, errors.New("invalid type assertion for FindByName")
+	}
+	return tag, args.Error(1)
+}
+
+func (m *MockTagsRepository) Update(_ context.Context, tag model.Tags) error {
 	args := m.Called(tag)
 	return args.Error(0)
 }
 
-func (m *MockTagsRepository) Delete(tagId int) error {
+func (m *MockTagsRepository) FindChildren(_ context.Context, tagId int) ([]model.Tags, error) {
+	args := m.Called(tagId)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsRepository) FindAncestors(_ context.Context, tagId int) ([]model.Tags, error) {
+	args := m.Called(tagId)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsRepository) FindTree(_ context.Context, maxDepth int, limit int) ([]model.Tags, bool, error) {
+	args := m.Called(maxDepth, limit)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, false, args.Error(2)
+	}
+	return tags, args.Bool(1), args.Error(2)
+}
+
+func (m *MockTagsRepository) Delete(_ context.Context, tagId int, version int, strategy string) error {
+	args := m.Called(tagId, version, strategy)
+	return args.Error(0)
+}
+
+const testRetention = 24 * time.Hour
+
+var testSigner = cursor.NewSigner([]byte("test-secret"))
+
+func (m *MockTagsRepository) DeletePermanently(_ context.Context, tagId int, version int, strategy string) error {
+	args := m.Called(tagId, version, strategy)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) FindTrash(_ context.Context, query data.TagQuery) ([]model.Tags, int64, error) {
+	args := m.Called(query)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, 0, errors.New("invalid type assertion for FindTrash")
+	}
+	return tags, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTagsRepository) Restore(_ context.Context, tagId int) error {
 	args := m.Called(tagId)
 	return args.Error(0)
 }
 
+func (m *MockTagsRepository) PurgeTrash(_ context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTagsRepository) SaveBatch(_ context.Context, tags []model.Tags) ([]model.Tags, error) {
+	args := m.Called(tags)
+	saved, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return saved, args.Error(1)
+}
+
+func (m *MockTagsRepository) UpdateBatch(_ context.Context, tags []model.Tags) error {
+	args := m.Called(tags)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) DeleteBatch(_ context.Context, tagIds []int) error {
+	args := m.Called(tagIds)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) SaveAlias(_ context.Context, alias model.TagAlias) error {
+	args := m.Called(alias)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) DeleteAlias(_ context.Context, tagId int, name string) error {
+	args := m.Called(tagId, name)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) Merge(_ context.Context, targetId int, sourceIds []int, version int) error {
+	args := m.Called(targetId, sourceIds, version)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) Attach(_ context.Context, tagging model.Tagging) error {
+	args := m.Called(tagging)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) Detach(_ context.Context, tagging model.Tagging) error {
+	args := m.Called(tagging)
+	return args.Error(0)
+}
+
+func (m *MockTagsRepository) FindByResource(_ context.Context, resourceType string, resourceId string) ([]model.Tags, error) {
+	args := m.Called(resourceType, resourceId)
+	tags, ok := args.Get(0).([]model.Tags)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return tags, args.Error(1)
+}
+
+func (m *MockTagsRepository) FindResources(_ context.Context, tagId int, query data.TaggingQuery) ([]model.Tagging, int64, error) {
+	args := m.Called(tagId, query)
+	taggings, ok := args.Get(0).([]model.Tagging)
+	if !ok {
+		return nil, 0, args.Error(2)
+	}
+	return taggings, args.Get(1).(int64), args.Error(2)
+}
+
+var ctx = context.Background()
+
 func setupTest() (*MockTagsRepository, service.TagsService) {
 	mockRepo := new(MockTagsRepository)
 	validate := validator.New()
-	tagsService := service.NewTagsServiceImpl(mockRepo, validate)
+	tagsService := service.NewTagsServiceImpl(mockRepo, validate, testSigner, config.TrashRetention(testRetention))
 	return mockRepo, tagsService
 }
 
@@ -62,14 +209,14 @@ func TestCreateTag(t *testing.T) {
 		mockRepo.On("Save", mock.Anything).Return(nil).Once()
 
 		tagRequest := data.TagRequest{Name: "NewTag"}
-		err := tagsService.Create(tagRequest)
+		err := tagsService.Create(ctx, tagRequest)
 		assert.Nil(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("should fail to create a tag with invalid data", func(t *testing.T) {
 		tagRequest := data.TagRequest{Name: ""}
-		err := tagsService.Create(tagRequest)
+		err := tagsService.Create(ctx, tagRequest)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "validation failed")
 	})
@@ -82,39 +229,161 @@ func TestFindAllTags(t *testing.T) {
 			{Id: 1, Name: "Tag1"},
 			{Id: 2, Name: "Tag2"},
 		}
-		mockRepo.On("FindAll").Return(tags, nil).Once()
+		defaultQuery := data.TagQuery{Page: 1, PageSize: data.DefaultPageSize}
+		mockRepo.On("FindAll", defaultQuery).Return(tags, int64(2), nil).Once()
 
-		result, err := tagsService.FindAll()
+		result, meta, err := tagsService.FindAll(ctx, data.TagQuery{})
 		assert.Nil(t, err)
 		assert.Len(t, result, 2)
+		assert.Equal(t, data.PageMeta{Page: 1, PageSize: data.DefaultPageSize, Total: 2, TotalPages: 1}, meta)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("should cap page size at the server maximum", func(t *testing.T) {
+		cappedQuery := data.TagQuery{Page: 3, PageSize: data.MaxPageSize, Sort: "-name"}
+		mockRepo.On("FindAll", cappedQuery).Return([]model.Tags{}, int64(250), nil).Once()
+
+		result, meta, err := tagsService.FindAll(ctx, data.TagQuery{Page: 3, PageSize: 5000, Sort: "-name"})
+		assert.Nil(t, err)
+		assert.Empty(t, result)
+		assert.Equal(t, data.MaxPageSize, meta.PageSize)
+		assert.Equal(t, 3, meta.TotalPages)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown sort field", func(t *testing.T) {
+		_, _, err := tagsService.FindAll(ctx, data.TagQuery{Sort: "created"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
 	t.Run("should return error when finding all tags fails", func(t *testing.T) {
-		mockRepo.On("FindAll").Return([]model.Tags{}, errors.New("database error")).Once()
+		mockRepo.On("FindAll", mock.Anything).Return([]model.Tags{}, int64(0), errors.New("database error")).Once()
 
-		_, err := tagsService.FindAll()
+		_, _, err := tagsService.FindAll(ctx, data.TagQuery{})
 		assert.NotNil(t, err)
 		assert.Equal(t, "database error", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
 
+func TestFindAllTagsByCursor(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should return next cursor on first page", func(t *testing.T) {
+		expectedQuery := data.TagQuery{Page: 1, PageSize: 2, Sort: "name", Pagination: "cursor"}
+		var noCursor *data.TagCursor
+		mockRepo.On("FindAllByCursor", expectedQuery, noCursor).
+			Return([]model.Tags{{Id: 3, Name: "gin"}, {Id: 1, Name: "go"}}, true, nil).Once()
+
+		result, meta, err := tagsService.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "name", Pagination: "cursor"})
+		assert.Nil(t, err)
+		assert.Len(t, result, 2)
+		assert.Empty(t, meta.PrevCursor)
+		assert.NotEmpty(t, meta.NextCursor)
+
+		var next data.TagCursor
+		assert.Nil(t, testSigner.Decode(meta.NextCursor, &next))
+		assert.Equal(t, data.TagCursor{Id: 1, Name: "go", Sort: "name"}, next)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should pass decoded cursor to repository", func(t *testing.T) {
+		position := data.TagCursor{Id: 1, Name: "go", Sort: "name"}
+		token, _ := testSigner.Encode(position)
+		expectedQuery := data.TagQuery{Page: 1, PageSize: 2, Sort: "name", Cursor: token}
+		mockRepo.On("FindAllByCursor", expectedQuery, &position).
+			Return([]model.Tags{{Id: 4, Name: "gorm"}}, false, nil).Once()
+
+		result, meta, err := tagsService.FindAllByCursor(ctx, data.TagQuery{PageSize: 2, Sort: "name", Cursor: token})
+		assert.Nil(t, err)
+		assert.Len(t, result, 1)
+		assert.Empty(t, meta.NextCursor)
+
+		var prev data.TagCursor
+		assert.Nil(t, testSigner.Decode(meta.PrevCursor, &prev))
+		assert.Equal(t, data.TagCursor{Id: 4, Name: "gorm", Sort: "name", Backward: true}, prev)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a tampered cursor", func(t *testing.T) {
+		token, _ := cursor.NewSigner([]byte("forged")).Encode(data.TagCursor{Id: 1, Sort: "id"})
+
+		_, _, err := tagsService.FindAllByCursor(ctx, data.TagQuery{Cursor: token})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+
+	t.Run("should carry the filters in the cursor", func(t *testing.T) {
+		query := data.TagQuery{PageSize: 1, NamePrefix: "go", Attributes: map[string][]string{"team": {"web", "api"}}}
+		mockRepo.On("FindAllByCursor", mock.Anything, mock.Anything).
+			Return([]model.Tags{{Id: 1, Name: "go"}}, true, nil).Twice()
+
+		_, meta, err := tagsService.FindAllByCursor(ctx, query)
+		assert.Nil(t, err)
+
+		var next data.TagCursor
+		assert.Nil(t, testSigner.Decode(meta.NextCursor, &next))
+		assert.NotEmpty(t, next.Filters)
+
+		query.Cursor = meta.NextCursor
+		query.Attributes = map[string][]string{"team": {"api", "web"}}
+		_, _, err = tagsService.FindAllByCursor(ctx, query)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a cursor issued for other filters", func(t *testing.T) {
+		token, _ := testSigner.Encode(data.TagCursor{Id: 1, Sort: "id"})
+
+		for _, query := range []data.TagQuery{
+			{Cursor: token, NameContains: "go"},
+			{Cursor: token, NamePrefix: "go"},
+			{Cursor: token, CreatedAfter: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Cursor: token, UpdatedSince: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Cursor: token, Attributes: map[string][]string{"team": {"web"}}},
+		} {
+			_, _, err := tagsService.FindAllByCursor(ctx, query)
+			assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		}
+	})
+
+	t.Run("should reject a cursor issued for another sort order", func(t *testing.T) {
+		token, _ := testSigner.Encode(data.TagCursor{Id: 1, Sort: "id"})
+
+		_, _, err := tagsService.FindAllByCursor(ctx, data.TagQuery{Cursor: token, Sort: "-id"})
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "validation failed")
+	})
+}
+
 func TestFindTagById(t *testing.T) {
 	mockRepo, tagsService := setupTest()
 	t.Run("should find a tag by ID successfully", func(t *testing.T) {
-		mockRepo.On("FindById", "1").Return(model.Tags{Id: 1, Name: "Tag1"}, nil).Once()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "Tag1"}, nil).Once()
 
-		result, err := tagsService.FindById("1")
+		result, err := tagsService.FindById(ctx, 1)
 		assert.Nil(t, err)
 		assert.Equal(t, "Tag1", result.Name)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("should expose timestamps in UTC", func(t *testing.T) {
+		createdAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+		tag := model.Tags{Id: 2, Name: "Tag2", CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Hour)}
+		mockRepo.On("FindById", 2).Return(tag, nil).Once()
+
+		result, err := tagsService.FindById(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-05-01T00:00:00Z", result.CreatedAt)
+		assert.Equal(t, "2024-05-01T01:00:00Z", result.UpdatedAt)
+		assert.Empty(t, result.DeletedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("should return error when tag ID is not found", func(t *testing.T) {
-		mockRepo.On("FindById", "999").Return(model.Tags{}, errors.New("resource not found")).Once()
+		mockRepo.On("FindById", 999).Return(model.Tags{}, errors.New("resource not found")).Once()
 
-		_, err := tagsService.FindById("999")
+		_, err := tagsService.FindById(ctx, 999)
 		assert.NotNil(t, err)
 		assert.Equal(t, "resource not found", err.Error())
 		mockRepo.AssertExpectations(t)
@@ -124,54 +393,780 @@ func TestFindTagById(t *testing.T) {
 func TestUpdateTag(t *testing.T) {
 	mockRepo, tagsService := setupTest()
 	t.Run("should update a tag successfully", func(t *testing.T) {
-		mockRepo.On("FindById", "1").Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
 		mockRepo.On("Update", mock.Anything).Return(nil).Once()
 
-		err := tagsService.Update("1", data.TagRequest{Name: "UpdatedTag"})
+		err := tagsService.Update(ctx, 1, data.TagRequest{Name: "UpdatedTag"}, 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should update when If-Match version matches", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag", Version: 3}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "UpdatedTag", Version: 3}).Return(nil).Once()
+
+		err := tagsService.Update(ctx, 1, data.TagRequest{Name: "UpdatedTag"}, 3)
 		assert.Nil(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("should reject a stale If-Match version", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag", Version: 4}, nil).Once()
+
+		err := tagsService.Update(ctx, 1, data.TagRequest{Name: "UpdatedTag"}, 3)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("should fail to update a tag with invalid data", func(t *testing.T) {
-		err := tagsService.Update("1", data.TagRequest{Name: ""})
+		err := tagsService.Update(ctx, 1, data.TagRequest{Name: ""}, 0)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "validation failed")
 	})
 
 	t.Run("should return error when tag ID is not found for update", func(t *testing.T) {
-		mockRepo.On("FindById", "0").Return(model.Tags{}, errors.New("resource not found")).Once()
+		mockRepo.On("FindById", 0).Return(model.Tags{}, errors.New("resource not found")).Once()
 
-		err := tagsService.Update("0", data.TagRequest{Name: "test"})
+		err := tagsService.Update(ctx, 0, data.TagRequest{Name: "test"}, 0)
 		assert.NotNil(t, err)
 		assert.Contains(t, err.Error(), "resource not found")
 	})
 
 	t.Run("should return error when updating a non-existent tag", func(t *testing.T) {
-		mockRepo.On("FindById", "999").Return(model.Tags{}, nil).Once()
+		mockRepo.On("FindById", 999).Return(model.Tags{}, nil).Once()
 
-		err := tagsService.Update("999", data.TagRequest{Name: "NonExistentTag"})
+		err := tagsService.Update(ctx, 999, data.TagRequest{Name: "NonExistentTag"}, 0)
 		assert.NotNil(t, err)
 		assert.Equal(t, "resource not found", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
 
+func TestPatchTag(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should apply a merge patch", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag", Version: 2}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "MergedTag", Version: 2}).Return(nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.MergePatchContentType, []byte(`{"name":"MergedTag"}`), 2)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should apply a JSON patch", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "PatchedTag"}).Return(nil).Once()
+
+		patch := `[{"op":"test","path":"/name","value":"OldTag"},{"op":"replace","path":"/name","value":"PatchedTag"}]`
+		err := tagsService.Patch(ctx, 1, data.JSONPatchContentType, []byte(patch), 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should apply a JSON patch to unset optional fields", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "OldTag", Description: "Go things", Color: "#3366cc",
+			Attributes: model.TagAttributes{"team": "web"}}).Return(nil).Once()
+
+		patch := `[
+			{"op":"test","path":"/parent_id","value":null},
+			{"op":"test","path":"/icon","value":null},
+			{"op":"replace","path":"/description","value":"Go things"},
+			{"op":"replace","path":"/color","value":"#3366CC"},
+			{"op":"replace","path":"/attributes","value":{"team":"web"}},
+			{"op":"remove","path":"/icon"}
+		]`
+		err := tagsService.Patch(ctx, 1, data.JSONPatchContentType, []byte(patch), 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should clear optional fields with a JSON patch", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag", Description: "Go things", Color: "#3366cc",
+			Attributes: model.TagAttributes{"team": "web"}}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "OldTag"}).Return(nil).Once()
+
+		patch := `[
+			{"op":"remove","path":"/description"},
+			{"op":"replace","path":"/color","value":null},
+			{"op":"remove","path":"/attributes"}
+		]`
+		err := tagsService.Patch(ctx, 1, data.JSONPatchContentType, []byte(patch), 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a JSON patch whose test operation fails", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+
+		patch := `[{"op":"test","path":"/name","value":"Other"},{"op":"replace","path":"/name","value":"PatchedTag"}]`
+		err := tagsService.Patch(ctx, 1, data.JSONPatchContentType, []byte(patch), 0)
+		assert.ErrorIs(t, err, helper.ErrInvalidPatch)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a malformed patch document", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.MergePatchContentType, []byte(`{"name":`), 0)
+		assert.ErrorIs(t, err, helper.ErrInvalidPatch)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should validate the patched tag", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.MergePatchContentType, []byte(`{"name":null}`), 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag"}, nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.JSONPatchContentType, []byte(`[{"op":"add","path":"/id","value":5}]`), 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a stale If-Match version", func(t *testing.T) {
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "OldTag", Version: 3}, nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.MergePatchContentType, []byte(`{"name":"MergedTag"}`), 2)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return not found for missing tag", func(t *testing.T) {
+		mockRepo.On("FindById", 999).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+		err := tagsService.Patch(ctx, 999, data.MergePatchContentType, []byte(`{"name":"MergedTag"}`), 0)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
 func TestDeleteTag(t *testing.T) {
 	mockRepo, tagsService := setupTest()
 	t.Run("should delete a tag successfully", func(t *testing.T) {
-		mockRepo.On("Delete", 1).Return(nil).Once()
+		mockRepo.On("Delete", 1, 0, data.DeleteReject).Return(nil).Once()
 
-		err := tagsService.Delete(1)
+		err := tagsService.Delete(ctx, 1, 0, data.DeleteReject)
 		assert.Nil(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("should return error when deleting a tag fails", func(t *testing.T) {
-		mockRepo.On("Delete", 999).Return(errors.New("delete failed")).Once()
+		mockRepo.On("Delete", 999, 0, data.DeleteReject).Return(errors.New("delete failed")).Once()
 
-		err := tagsService.Delete(999)
+		err := tagsService.Delete(ctx, 999, 0, data.DeleteReject)
 		assert.NotNil(t, err)
 		assert.Equal(t, "delete failed", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestTrash(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should list trashed tags with deletion time", func(t *testing.T) {
+		deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CST", 8*3600))
+		trashed := []model.Tags{{Id: 1, Name: "Tag1", DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}}}
+		mockRepo.On("FindTrash", data.TagQuery{Page: 1, PageSize: data.DefaultPageSize}).Return(trashed, int64(1), nil).Once()
+
+		result, meta, err := tagsService.FindTrash(ctx, data.TagQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, "2024-05-01T02:00:00Z", result[0].DeletedAt)
+		assert.Equal(t, int64(1), meta.Total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should restore a tag", func(t *testing.T) {
+		mockRepo.On("Restore", 1).Return(nil).Once()
+
+		err := tagsService.Restore(ctx, 1)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should delete a tag permanently", func(t *testing.T) {
+		mockRepo.On("DeletePermanently", 1, 0, data.DeleteReject).Return(nil).Once()
+
+		err := tagsService.DeletePermanently(ctx, 1, 0, data.DeleteReject)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should purge trash older than the retention", func(t *testing.T) {
+		mockRepo.On("PurgeTrash", mock.MatchedBy(func(deletedBefore time.Time) bool {
+			return time.Since(deletedBefore) >= testRetention && time.Since(deletedBefore) < testRetention+time.Minute
+		})).Return(int64(3), nil).Once()
+
+		var buffer bytes.Buffer
+		logger, _ := logging.New(&buffer, slog.LevelInfo, "json")
+		logged := logging.NewContext(ctx, logger.With("request_id", "req-1"))
+
+		result, err := tagsService.PurgeTrash(logged)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), result.Purged)
+		assert.Contains(t, buffer.String(), `"msg":"trash purged","request_id":"req-1","purged":3`)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBulkCreate(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should create all tags in one batch", func(t *testing.T) {
+		mockRepo.On("SaveBatch", []model.Tags{{Name: "first"}, {Name: "second"}}).
+			Return([]model.Tags{{Id: 7, Name: "first"}, {Id: 8, Name: "second"}}, nil).Once()
+
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "first"}, {Name: "second"}}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.BulkResult{{Index: 0, Id: 7, Success: true}, {Index: 1, Id: 8, Success: true}}, results)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should write nothing when an item is invalid", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "first"}, {Name: "no"}}, false)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		assert.True(t, results[0].Success)
+		assert.False(t, results[1].Success)
+		assert.Contains(t, results[1].Error, "validation failed")
+		mockRepo.AssertNotCalled(t, "SaveBatch", mock.Anything)
+	})
+
+	t.Run("should reject duplicate names within the batch", func(t *testing.T) {
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "golang"}, {Name: "GoLang"}}, false)
+		assert.ErrorIs(t, err, helper.ErrConflict)
+		assert.Contains(t, results[1].Error, "same name as item 0")
+	})
+
+	t.Run("should report the batch as rolled back on database conflict", func(t *testing.T) {
+		mockRepo.On("SaveBatch", mock.Anything).Return(nil, helper.ErrConflict).Once()
+
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "first"}, {Name: "second"}}, false)
+		assert.ErrorIs(t, err, helper.ErrConflict)
+		assert.Equal(t, "rolled back: resource already exists", results[0].Error)
+		assert.Equal(t, "rolled back: resource already exists", results[1].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should blame every conflicting item when the batch is rolled back", func(t *testing.T) {
+		failure := helper.BatchItemErrors{{Index: 0, Err: helper.ErrConflict}, {Index: 2, Err: helper.ErrConflict}}
+		mockRepo.On("SaveBatch", mock.Anything).Return(nil, failure).Once()
+
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "taken"}, {Name: "fresh"}, {Name: "aliased"}}, false)
+		assert.ErrorIs(t, err, helper.ErrConflict)
+		assert.Equal(t, "resource already exists", results[0].Error)
+		assert.Equal(t, "rolled back: resource already exists", results[1].Error)
+		assert.Equal(t, "resource already exists", results[2].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should create items independently in partial mode", func(t *testing.T) {
+		mockRepo.On("SaveBatch", []model.Tags{{Name: "first"}}).Return([]model.Tags{{Id: 7, Name: "first"}}, nil).Once()
+		mockRepo.On("SaveBatch", []model.Tags{{Name: "taken"}}).Return(nil, helper.BatchItemErrors{{Index: 0, Err: helper.ErrConflict}}).Once()
+
+		results, err := tagsService.BulkCreate(ctx, []data.TagRequest{{Name: "first"}, {Name: "no"}, {Name: "taken"}}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, data.BulkResult{Index: 0, Id: 7, Success: true}, results[0])
+		assert.Contains(t, results[1].Error, "validation failed")
+		assert.Equal(t, "resource already exists", results[2].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject empty and oversized batches", func(t *testing.T) {
+		_, err := tagsService.BulkCreate(ctx, []data.TagRequest{}, false)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+
+		_, err = tagsService.BulkCreate(ctx, make([]data.TagRequest, data.MaxBulkItems+1), true)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+	})
+}
+
+func TestBulkUpdate(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should update all tags in one batch", func(t *testing.T) {
+		mockRepo.On("UpdateBatch", []model.Tags{{Id: 1, Name: "first", Version: 2}, {Id: 2, Name: "second"}}).Return(nil).Once()
+
+		results, err := tagsService.BulkUpdate(ctx, []data.BulkTagUpdateRequest{{Id: 1, Version: 2, TagRequest: data.TagRequest{Name: "first"}}, {Id: 2, TagRequest: data.TagRequest{Name: "second"}}}, false)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.BulkResult{{Index: 0, Id: 1, Success: true}, {Index: 1, Id: 2, Success: true}}, results)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should blame the failing item when the batch is rolled back", func(t *testing.T) {
+		failure := &helper.BatchItemError{Index: 1, Err: helper.ErrPreconditionFailed}
+		mockRepo.On("UpdateBatch", mock.Anything).Return(failure).Once()
+
+		results, err := tagsService.BulkUpdate(ctx, []data.BulkTagUpdateRequest{{Id: 1, TagRequest: data.TagRequest{Name: "first"}}, {Id: 2, Version: 1, TagRequest: data.TagRequest{Name: "second"}}}, false)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+		assert.Equal(t, "rolled back: resource has been modified", results[0].Error)
+		assert.Equal(t, "resource has been modified", results[1].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should update items independently in partial mode", func(t *testing.T) {
+		mockRepo.On("UpdateBatch", []model.Tags{{Id: 1, Name: "first"}}).Return(nil).Once()
+		mockRepo.On("UpdateBatch", []model.Tags{{Id: 9, Name: "missing"}}).Return(&helper.BatchItemError{Index: 0, Err: helper.ErrNotFound}).Once()
+
+		results, err := tagsService.BulkUpdate(ctx, []data.BulkTagUpdateRequest{{Id: 1, TagRequest: data.TagRequest{Name: "first"}}, {Id: 9, TagRequest: data.TagRequest{Name: "missing"}}}, true)
+		assert.Nil(t, err)
+		assert.True(t, results[0].Success)
+		assert.Equal(t, data.BulkResult{Index: 1, Id: 9, Error: "resource not found"}, results[1])
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should update the same fields as a single update", func(t *testing.T) {
+		parentId := 5
+		mockRepo.On("FindById", 5).Return(model.Tags{Id: 5, Name: "parent"}, nil).Once()
+		mockRepo.On("FindAncestors", 5).Return([]model.Tags{}, nil).Once()
+		mockRepo.On("UpdateBatch", []model.Tags{{Id: 1, Name: "first", ParentId: &parentId, Description: "Docs", Color: "#ff0000",
+			Attributes: model.TagAttributes{"team": "web"}}}).Return(nil).Once()
+
+		results, err := tagsService.BulkUpdate(ctx, []data.BulkTagUpdateRequest{{Id: 1, TagRequest: data.TagRequest{Name: "first",
+			ParentId: &parentId, Description: "Docs", Color: "#FF0000", Attributes: map[string]any{"team": "web"}}}}, false)
+		assert.Nil(t, err)
+		assert.True(t, results[0].Success)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an item that would become its own ancestor", func(t *testing.T) {
+		selfId := 3
+		results, err := tagsService.BulkUpdate(ctx, []data.BulkTagUpdateRequest{{Id: 3, TagRequest: data.TagRequest{Name: "cycle",
+			ParentId: &selfId}}}, true)
+		assert.Nil(t, err)
+		assert.False(t, results[0].Success)
+		assert.Contains(t, results[0].Error, "validation failed")
+	})
+}
+
+func TestBulkDelete(t *testing.T) {
+	mockRepo, tagsService := setupTest()
+	t.Run("should delete all tags in one batch", func(t *testing.T) {
+		mockRepo.On("DeleteBatch", []int{1, 2}).Return(nil).Once()
+
+		results, err := tagsService.BulkDelete(ctx, []int{1, 2}, false)
+		assert.Nil(t, err)
+		assert.True(t, results[0].Success)
+		assert.True(t, results[1].Success)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should report missing id when the batch is rolled back", func(t *testing.T) {
+		mockRepo.On("DeleteBatch", []int{1, 9}).Return(&helper.BatchItemError{Index: 1, Err: helper.ErrNotFound}).Once()
+
+		results, err := tagsService.BulkDelete(ctx, []int{1, 9}, false)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		assert.Equal(t, "rolled back: resource not found", results[0].Error)
+		assert.Equal(t, "resource not found", results[1].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should blame every blocked item when the batch is rolled back", func(t *testing.T) {
+		failure := helper.BatchItemErrors{{Index: 0, Err: helper.ErrHasChildren}, {Index: 2, Err: helper.ErrNotFound}}
+		mockRepo.On("DeleteBatch", []int{1, 2, 9}).Return(failure).Once()
+
+		results, err := tagsService.BulkDelete(ctx, []int{1, 2, 9}, false)
+		assert.ErrorIs(t, err, helper.ErrHasChildren)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		assert.Equal(t, "resource has children", results[0].Error)
+		assert.Equal(t, "rolled back: resource has children", results[1].Error)
+		assert.Equal(t, "resource not found", results[2].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should delete items independently in partial mode", func(t *testing.T) {
+		mockRepo.On("DeleteBatch", []int{1}).Return(nil).Once()
+		mockRepo.On("DeleteBatch", []int{9}).Return(&helper.BatchItemError{Index: 0, Err: helper.ErrNotFound}).Once()
+
+		results, err := tagsService.BulkDelete(ctx, []int{1, 9}, true)
+		assert.Nil(t, err)
+		assert.Equal(t, data.BulkResult{Index: 0, Id: 1, Success: true}, results[0])
+		assert.Equal(t, data.BulkResult{Index: 1, Id: 9, Error: "resource not found"}, results[1])
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestHierarchy(t *testing.T) {
+	t.Run("should create a tag below an existing parent", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "lang"}, nil).Once()
+		mockRepo.On("Save", model.Tags{Name: "golang", ParentId: intPtr(1)}).Return(nil).Once()
+
+		err := tagsService.Create(ctx, data.TagRequest{Name: "golang", ParentId: intPtr(1)})
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject a parent that does not exist", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 9).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+		err := tagsService.Create(ctx, data.TagRequest{Name: "golang", ParentId: intPtr(9)})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should move a tag below another tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", Version: 2}, nil).Once()
+		mockRepo.On("FindById", 3).Return(model.Tags{Id: 3, Name: "web"}, nil).Once()
+		mockRepo.On("FindAncestors", 3).Return([]model.Tags{}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 4, Name: "gin", ParentId: intPtr(3), Version: 2}).Return(nil).Once()
+
+		err := tagsService.Move(ctx, 4, intPtr(3), 2)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should make a tag a root", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", ParentId: intPtr(2), Version: 1}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 4, Name: "gin", Version: 1}).Return(nil).Once()
+
+		err := tagsService.Move(ctx, 4, nil, 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject moving a tag below itself", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 2).Return(model.Tags{Id: 2, Name: "golang", Version: 1}, nil).Once()
+
+		err := tagsService.Move(ctx, 2, intPtr(2), 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("should reject moving a tag below its descendant", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "lang", Version: 1}, nil).Once()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", ParentId: intPtr(2)}, nil).Once()
+		mockRepo.On("FindAncestors", 4).Return([]model.Tags{{Id: 1, Name: "lang"}, {Id: 2, Name: "golang"}}, nil).Once()
+
+		err := tagsService.Move(ctx, 1, intPtr(4), 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		assert.Contains(t, err.Error(), "descendants")
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("should reject a stale move", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", Version: 3}, nil).Once()
+
+		err := tagsService.Move(ctx, 4, nil, 2)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+	})
+
+	t.Run("should keep the parent through a merge patch", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", ParentId: intPtr(2), Version: 1}, nil).Once()
+		mockRepo.On("FindById", 2).Return(model.Tags{Id: 2, Name: "golang"}, nil).Once()
+		mockRepo.On("FindAncestors", 2).Return([]model.Tags{{Id: 1, Name: "lang"}}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 4, Name: "gin-gonic", ParentId: intPtr(2), Version: 1}).Return(nil).Once()
+
+		err := tagsService.Patch(ctx, 4, data.MergePatchContentType, []byte(`{"name": "gin-gonic"}`), 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should list children and ancestors of an existing tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 2).Return(model.Tags{Id: 2, Name: "golang"}, nil).Twice()
+		mockRepo.On("FindChildren", 2).Return([]model.Tags{{Id: 4, Name: "gin", ParentId: intPtr(2)}}, nil).Once()
+		mockRepo.On("FindAncestors", 2).Return([]model.Tags{{Id: 1, Name: "lang"}}, nil).Once()
+
+		children, err := tagsService.FindChildren(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.TagResponse{{Id: 4, Name: "gin", ParentId: intPtr(2)}}, children)
+
+		ancestors, err := tagsService.FindAncestors(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.TagResponse{{Id: 1, Name: "lang"}}, ancestors)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not list children of a missing tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 9).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+		_, err := tagsService.FindChildren(ctx, 9)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "FindChildren", mock.Anything)
+	})
+
+	t.Run("should nest the tree and keep orphans at the root", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindTree", data.MaxTreeDepth, data.MaxTreeNodes).Return([]model.Tags{
+			{Id: 4, Name: "gin", ParentId: intPtr(2)},
+			{Id: 2, Name: "golang", ParentId: intPtr(1)},
+			{Id: 1, Name: "lang"},
+			{Id: 7, Name: "orphan", ParentId: intPtr(6)},
+		}, false, nil).Once()
+
+		tree, meta, err := tagsService.FindTree(ctx, data.TagTreeQuery{})
+		assert.Nil(t, err)
+		assert.Equal(t, data.TagTreeMeta{MaxDepth: data.MaxTreeDepth, Nodes: 4}, meta)
+		assert.Equal(t, []data.TagTreeNode{
+			{
+				TagResponse: data.TagResponse{Id: 1, Name: "lang"},
+				Children: []data.TagTreeNode{{
+					TagResponse: data.TagResponse{Id: 2, Name: "golang", ParentId: intPtr(1)},
+					Children: []data.TagTreeNode{{
+						TagResponse: data.TagResponse{Id: 4, Name: "gin", ParentId: intPtr(2)},
+						Children:    []data.TagTreeNode{},
+					}},
+				}},
+			},
+			{
+				TagResponse: data.TagResponse{Id: 7, Name: "orphan", ParentId: intPtr(6)},
+				Children:    []data.TagTreeNode{},
+			},
+		}, tree)
+	})
+
+	t.Run("should bound the tree by the requested depth", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindTree", 1, data.MaxTreeNodes).Return([]model.Tags{{Id: 1, Name: "lang"}}, true, nil).Once()
+
+		tree, meta, err := tagsService.FindTree(ctx, data.TagTreeQuery{MaxDepth: 1})
+		assert.Nil(t, err)
+		assert.Len(t, tree, 1)
+		assert.Equal(t, data.TagTreeMeta{MaxDepth: 1, Nodes: 1, Truncated: true}, meta)
+	})
+
+	t.Run("should reject a max depth out of range", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+
+		_, _, err := tagsService.FindTree(ctx, data.TagTreeQuery{MaxDepth: data.MaxTreeDepth + 1})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "FindTree", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should pass the delete strategy through", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("Delete", 2, 0, data.DeleteCascade).Return(nil).Once()
+		mockRepo.On("DeletePermanently", 2, 0, data.DeleteReparent).Return(nil).Once()
+
+		assert.Nil(t, tagsService.Delete(ctx, 2, 0, data.DeleteCascade))
+		assert.Nil(t, tagsService.DeletePermanently(ctx, 2, 0, data.DeleteReparent))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an unknown delete strategy", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+
+		err := tagsService.Delete(ctx, 2, 0, "orphan")
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAliases(t *testing.T) {
+	t.Run("should resolve an alias to its canonical tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		tag := model.Tags{Id: 3, Name: "JavaScript", Version: 2, Aliases: []model.TagAlias{{TagId: 3, Name: "js"}}}
+		mockRepo.On("FindByName", "JS").Return(tag, nil).Once()
+
+		result, err := tagsService.FindByName(ctx, "JS")
+		assert.Nil(t, err)
+		assert.Equal(t, data.TagResponse{Id: 3, Name: "JavaScript", Aliases: []string{"js"}, Version: 2}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should add an alias to an existing tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 3).Return(model.Tags{Id: 3, Name: "JavaScript"}, nil).Once()
+		mockRepo.On("SaveAlias", model.TagAlias{TagId: 3, Name: "js"}).Return(nil).Once()
+
+		err := tagsService.AddAlias(ctx, 3, data.AliasRequest{Name: "js"})
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not add an alias to a missing tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 9).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+		err := tagsService.AddAlias(ctx, 9, data.AliasRequest{Name: "js"})
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "SaveAlias", mock.Anything)
+	})
+
+	t.Run("should reject an empty alias", func(t *testing.T) {
+		_, tagsService := setupTest()
+
+		err := tagsService.AddAlias(ctx, 3, data.AliasRequest{Name: ""})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+	})
+}
+
+func TestMergeTags(t *testing.T) {
+	t.Run("should merge sources into the target", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 3).Return(model.Tags{Id: 3, Name: "javascript", Version: 4}, nil).Once()
+		mockRepo.On("FindAncestors", 3).Return([]model.Tags{}, nil).Once()
+		mockRepo.On("Merge", 3, []int{5, 6}, 4).Return(nil).Once()
+
+		err := tagsService.Merge(ctx, 3, data.MergeTagRequest{SourceIds: []int{5, 6}}, 4)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject merging a tag into itself", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+
+		err := tagsService.Merge(ctx, 3, data.MergeTagRequest{SourceIds: []int{5, 3}}, 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject duplicate and missing sources", func(t *testing.T) {
+		_, tagsService := setupTest()
+
+		err := tagsService.Merge(ctx, 3, data.MergeTagRequest{SourceIds: []int{5, 5}}, 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+
+		err = tagsService.Merge(ctx, 3, data.MergeTagRequest{}, 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+	})
+
+	t.Run("should reject merging an ancestor into its descendant", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 4).Return(model.Tags{Id: 4, Name: "gin", Version: 1}, nil).Once()
+		mockRepo.On("FindAncestors", 4).Return([]model.Tags{{Id: 1, Name: "lang"}, {Id: 2, Name: "golang"}}, nil).Once()
+
+		err := tagsService.Merge(ctx, 4, data.MergeTagRequest{SourceIds: []int{2}}, 0)
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject a stale target version", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 3).Return(model.Tags{Id: 3, Name: "javascript", Version: 5}, nil).Once()
+
+		err := tagsService.Merge(ctx, 3, data.MergeTagRequest{SourceIds: []int{5}}, 4)
+		assert.ErrorIs(t, err, helper.ErrPreconditionFailed)
+	})
+}
+
+func TestTaggings(t *testing.T) {
+	article := data.ResourceRef{ResourceType: "article", ResourceId: "a-42"}
+
+	t.Run("should attach an active tag to a resource", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "golang"}, nil).Once()
+		mockRepo.On("Attach", model.Tagging{TagId: 1, ResourceType: "article", ResourceId: "a-42"}).Return(nil).Once()
+
+		err := tagsService.Attach(ctx, 1, article)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not attach a missing tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 9).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+		err := tagsService.Attach(ctx, 9, article)
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Attach", mock.Anything)
+	})
+
+	t.Run("should reject an oversized resource type", func(t *testing.T) {
+		_, tagsService := setupTest()
+
+		err := tagsService.Detach(ctx, 1, data.ResourceRef{ResourceType: strings.Repeat("x", 101), ResourceId: "1"})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+	})
+
+	t.Run("should list tags of a resource", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindByResource", "article", "a-42").Return([]model.Tags{{Id: 1, Name: "golang"}}, nil).Once()
+
+		tags, err := tagsService.FindByResource(ctx, article)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.TagResponse{{Id: 1, Name: "golang"}}, tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should page through resources of a tag", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		taggedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		query := data.TaggingQuery{Page: 2, PageSize: 1, ResourceType: "article"}
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "golang"}, nil).Once()
+		mockRepo.On("FindResources", 1, query).
+			Return([]model.Tagging{{TagId: 1, ResourceType: "article", ResourceId: "a-42", CreatedAt: taggedAt}}, int64(3), nil).Once()
+
+		resources, meta, err := tagsService.FindResources(ctx, 1, query)
+		assert.Nil(t, err)
+		assert.Equal(t, []data.ResourceResponse{{ResourceType: "article", ResourceId: "a-42", TaggedAt: "2024-05-01T10:00:00Z"}}, resources)
+		assert.Equal(t, data.PageMeta{Page: 2, PageSize: 1, Total: 3, TotalPages: 3}, meta)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTagMetadata(t *testing.T) {
+	t.Run("should store metadata with a lower case color", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("Save", model.Tags{Name: "platform", Description: "Shared services", Color: "#aabbcc", Icon: "server",
+			Attributes: model.TagAttributes{"team": "platform"}}).Return(nil).Once()
+
+		err := tagsService.Create(ctx, data.TagRequest{Name: "platform", Description: "Shared services", Color: "#AABBCC", Icon: "server",
+			Attributes: map[string]any{"team": "platform"}})
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject invalid metadata", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+
+		requests := []data.TagRequest{
+			{Name: "platform", Color: "blue"},
+			{Name: "platform", Color: "#12345"},
+			{Name: "platform", Icon: strings.Repeat("a", 65)},
+			{Name: "platform", Attributes: map[string]any{"": "empty key"}},
+		}
+		for _, request := range requests {
+			err := tagsService.Create(ctx, request)
+			assert.ErrorIs(t, err, helper.ErrFailedValidation, request)
+		}
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should merge patch attributes", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "platform", Color: "#336699",
+			Attributes: model.TagAttributes{"team": "platform", "tier": "1"}}, nil).Once()
+		mockRepo.On("Update", model.Tags{Id: 1, Name: "platform", Color: "#336699",
+			Attributes: model.TagAttributes{"team": "platform", "owner": "ops"}}).Return(nil).Once()
+
+		err := tagsService.Patch(ctx, 1, data.MergePatchContentType, []byte(`{"attributes":{"tier":null,"owner":"ops"}}`), 0)
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return metadata in responses", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+		mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "platform", Color: "#336699", Icon: "server",
+			Attributes: model.TagAttributes{"team": "platform"}}, nil).Once()
+
+		tag, err := tagsService.FindById(ctx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, "#336699", tag.Color)
+		assert.Equal(t, "server", tag.Icon)
+		assert.Equal(t, map[string]any{"team": "platform"}, tag.Attributes)
+	})
+
+	t.Run("should reject unusable attribute filters", func(t *testing.T) {
+		mockRepo, tagsService := setupTest()
+
+		_, _, err := tagsService.FindAll(ctx, data.TagQuery{Attributes: map[string][]string{`te"am`: {"platform"}}})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "FindAll", mock.Anything)
+	})
+}
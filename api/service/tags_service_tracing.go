@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"go-gin-project/api/repository"
+	"go-gin-project/config"
+	"go-gin-project/data"
+	"go-gin-project/helper/cursor"
+	"go-gin-project/helper/tracing"
+
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracedTagsService returns a TagsService that starts a span around every call.
+func NewTracedTagsService(tagsRepository repository.TagsRepository, validate *validator.Validate, cursorSigner *cursor.Signer, trashRetention config.TrashRetention, tracerProvider trace.TracerProvider) TagsService {
+	return &TracedTagsService{
+		next:   NewTagsServiceImpl(tagsRepository, validate, cursorSigner, trashRetention),
+		tracer: tracerProvider.Tracer(tracing.InstrumentationName),
+	}
+}
+
+// TracedTagsService wraps a TagsService with tracing.
+type TracedTagsService struct {
+	next   TagsService
+	tracer trace.Tracer
+}
+
+// trace starts a span named after a call, returning the context to make the call with; defer the returned function
+// with the address of the call's error.
+func (s *TracedTagsService) trace(ctx context.Context, method string) (context.Context, func(err *error)) {
+	ctx, span := s.tracer.Start(ctx, "TagsService."+method)
+	return ctx, func(err *error) {
+		tracing.End(span, *err)
+	}
+}
+
+func (s *TracedTagsService) Create(ctx context.Context, tag data.TagRequest) (err error) {
+	ctx, done := s.trace(ctx, "Create")
+	defer done(&err)
+	return s.next.Create(ctx, tag)
+}
+
+func (s *TracedTagsService) FindAll(ctx context.Context, query data.TagQuery) (tags []data.TagResponse, meta data.PageMeta, err error) {
+	ctx, done := s.trace(ctx, "FindAll")
+	defer done(&err)
+	return s.next.FindAll(ctx, query)
+}
+
+func (s *TracedTagsService) FindAllByCursor(ctx context.Context, query data.TagQuery) (tags []data.TagResponse, meta data.CursorMeta, err error) {
+	ctx, done := s.trace(ctx, "FindAllByCursor")
+	defer done(&err)
+	return s.next.FindAllByCursor(ctx, query)
+}
+
+func (s *TracedTagsService) FindById(ctx context.Context, tagId int) (tag data.TagResponse, err error) {
+	ctx, done := s.trace(ctx, "FindById")
+	defer done(&err)
+	return s.next.FindById(ctx, tagId)
+}
+
+func (s *TracedTagsService) FindByName(ctx context.Context, name string) (tag data.TagResponse, err error) {
+	ctx, done := s.trace(ctx, "FindByName")
+	defer done(&err)
+	return s.next.FindByName(ctx, name)
+}
+
+func (s *TracedTagsService) FindChildren(ctx context.Context, tagId int) (tags []data.TagResponse, err error) {
+	ctx, done := s.trace(ctx, "FindChildren")
+	defer done(&err)
+	return s.next.FindChildren(ctx, tagId)
+}
+
+func (s *TracedTagsService) FindAncestors(ctx context.Context, tagId int) (tags []data.TagResponse, err error) {
+	ctx, done := s.trace(ctx, "FindAncestors")
+	defer done(&err)
+	return s.next.FindAncestors(ctx, tagId)
+}
+
+func (s *TracedTagsService) FindTree(ctx context.Context, query data.TagTreeQuery) (tree []data.TagTreeNode, meta data.TagTreeMeta, err error) {
+	ctx, done := s.trace(ctx, "FindTree")
+	defer done(&err)
+	return s.next.FindTree(ctx, query)
+}
+
+func (s *TracedTagsService) Update(ctx context.Context, tagId int, tag data.TagRequest, version int) (err error) {
+	ctx, done := s.trace(ctx, "Update")
+	defer done(&err)
+	return s.next.Update(ctx, tagId, tag, version)
+}
+
+func (s *TracedTagsService) Patch(ctx context.Context, tagId int, contentType string, patch []byte, version int) (err error) {
+	ctx, done := s.trace(ctx, "Patch")
+	defer done(&err)
+	return s.next.Patch(ctx, tagId, contentType, patch, version)
+}
+
+func (s *TracedTagsService) Move(ctx context.Context, tagId int, parentId *int, version int) (err error) {
+	ctx, done := s.trace(ctx, "Move")
+	defer done(&err)
+	return s.next.Move(ctx, tagId, parentId, version)
+}
+
+func (s *TracedTagsService) Delete(ctx context.Context, tagId int, version int, strategy string) (err error) {
+	ctx, done := s.trace(ctx, "Delete")
+	defer done(&err)
+	return s.next.Delete(ctx, tagId, version, strategy)
+}
+
+func (s *TracedTagsService) DeletePermanently(ctx context.Context, tagId int, version int, strategy string) (err error) {
+	ctx, done := s.trace(ctx, "DeletePermanently")
+	defer done(&err)
+	return s.next.DeletePermanently(ctx, tagId, version, strategy)
+}
+
+func (s *TracedTagsService) FindTrash(ctx context.Context, query data.TagQuery) (tags []data.TagResponse, meta data.PageMeta, err error) {
+	ctx, done := s.trace(ctx, "FindTrash")
+	defer done(&err)
+	return s.next.FindTrash(ctx, query)
+}
+
+func (s *TracedTagsService) Restore(ctx context.Context, tagId int) (err error) {
+	ctx, done := s.trace(ctx, "Restore")
+	defer done(&err)
+	return s.next.Restore(ctx, tagId)
+}
+
+func (s *TracedTagsService) PurgeTrash(ctx context.Context) (purged data.PurgeResponse, err error) {
+	ctx, done := s.trace(ctx, "PurgeTrash")
+	defer done(&err)
+	return s.next.PurgeTrash(ctx)
+}
+
+func (s *TracedTagsService) BulkCreate(ctx context.Context, tags []data.TagRequest, partial bool) (results []data.BulkResult, err error) {
+	ctx, done := s.trace(ctx, "BulkCreate")
+	defer done(&err)
+	return s.next.BulkCreate(ctx, tags, partial)
+}
+
+func (s *TracedTagsService) BulkUpdate(ctx context.Context, tags []data.BulkTagUpdateRequest, partial bool) (results []data.BulkResult, err error) {
+	ctx, done := s.trace(ctx, "BulkUpdate")
+	defer done(&err)
+	return s.next.BulkUpdate(ctx, tags, partial)
+}
+
+func (s *TracedTagsService) BulkDelete(ctx context.Context, tagIds []int, partial bool) (results []data.BulkResult, err error) {
+	ctx, done := s.trace(ctx, "BulkDelete")
+	defer done(&err)
+	return s.next.BulkDelete(ctx, tagIds, partial)
+}
+
+func (s *TracedTagsService) AddAlias(ctx context.Context, tagId int, alias data.AliasRequest) (err error) {
+	ctx, done := s.trace(ctx, "AddAlias")
+	defer done(&err)
+	return s.next.AddAlias(ctx, tagId, alias)
+}
+
+func (s *TracedTagsService) DeleteAlias(ctx context.Context, tagId int, name string) (err error) {
+	ctx, done := s.trace(ctx, "DeleteAlias")
+	defer done(&err)
+	return s.next.DeleteAlias(ctx, tagId, name)
+}
+
+func (s *TracedTagsService) Merge(ctx context.Context, tagId int, merge data.MergeTagRequest, version int) (err error) {
+	ctx, done := s.trace(ctx, "Merge")
+	defer done(&err)
+	return s.next.Merge(ctx, tagId, merge, version)
+}
+
+func (s *TracedTagsService) Attach(ctx context.Context, tagId int, resource data.ResourceRef) (err error) {
+	ctx, done := s.trace(ctx, "Attach")
+	defer done(&err)
+	return s.next.Attach(ctx, tagId, resource)
+}
+
+func (s *TracedTagsService) Detach(ctx context.Context, tagId int, resource data.ResourceRef) (err error) {
+	ctx, done := s.trace(ctx, "Detach")
+	defer done(&err)
+	return s.next.Detach(ctx, tagId, resource)
+}
+
+func (s *TracedTagsService) FindByResource(ctx context.Context, resource data.ResourceRef) (tags []data.TagResponse, err error) {
+	ctx, done := s.trace(ctx, "FindByResource")
+	defer done(&err)
+	return s.next.FindByResource(ctx, resource)
+}
+
+func (s *TracedTagsService) FindResources(ctx context.Context, tagId int, query data.TaggingQuery) (resources []data.ResourceResponse, meta data.PageMeta, err error) {
+	ctx, done := s.trace(ctx, "FindResources")
+	defer done(&err)
+	return s.next.FindResources(ctx, tagId, query)
+}
@@ -0,0 +1,37 @@
+package service_test
+
+import (
+	"go-gin-project/api/service"
+	"go-gin-project/config"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedTagsService(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	mockRepo := new(MockTagsRepository)
+	tagsService := service.NewTracedTagsService(mockRepo, validator.New(), testSigner, config.TrashRetention(testRetention), provider)
+	mockRepo.On("FindById", 1).Return(model.Tags{Id: 1, Name: "Tag1"}, nil).Once()
+	mockRepo.On("FindById", 2).Return(model.Tags{}, helper.ErrNotFound).Once()
+
+	tag, err := tagsService.FindById(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "Tag1", tag.Name)
+	_, err = tagsService.FindById(ctx, 2)
+	assert.ErrorIs(t, err, helper.ErrNotFound)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "TagsService.FindById", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	mockRepo.AssertExpectations(t)
+}
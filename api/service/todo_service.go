@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-gin-project/api/repository"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type TodoService interface {
+	Create(ctx context.Context, todo data.TodoRequest) (data.TodoResponse, error)
+	FindAll(ctx context.Context, query data.TodoQuery) ([]data.TodoResponse, data.PageMeta, error)
+	FindById(ctx context.Context, todoId int) (data.TodoResponse, error)
+	Update(ctx context.Context, todoId int, todo data.TodoRequest) error
+	Delete(ctx context.Context, todoId int) error
+}
+
+func NewTodoServiceImpl(todoRepository repository.TodoRepository, validate *validator.Validate) TodoService {
+	return &TodoServiceImpl{
+		TodoRepository: todoRepository,
+		Validate:       validate,
+	}
+}
+
+type TodoServiceImpl struct {
+	TodoRepository repository.TodoRepository
+	Validate       *validator.Validate
+}
+
+// Create saves the todo and returns it as stored, with its tags loaded.
+func (t *TodoServiceImpl) Create(ctx context.Context, todo data.TodoRequest) (data.TodoResponse, error) {
+	todoModel, err := t.newTodo(ctx, todo)
+	if err != nil {
+		return data.TodoResponse{}, err
+	}
+
+	saved, err := t.TodoRepository.Save(ctx, todoModel)
+	if err != nil {
+		return data.TodoResponse{}, err
+	}
+	return t.FindById(ctx, saved.Id)
+}
+
+func (t *TodoServiceImpl) FindAll(ctx context.Context, query data.TodoQuery) ([]data.TodoResponse, data.PageMeta, error) {
+	err := t.Validate.Struct(query)
+	if err != nil {
+		return nil, data.PageMeta{}, helper.ErrFailedValidationWrap(err)
+	}
+	query.Normalize()
+
+	result, total, err := t.TodoRepository.FindAll(ctx, query)
+	if err != nil {
+		return nil, data.PageMeta{}, err
+	}
+
+	todos := []data.TodoResponse{}
+	for _, value := range result {
+		todos = append(todos, newTodoResponse(value))
+	}
+
+	return todos, data.NewPageMeta(query.Page, query.PageSize, total), nil
+}
+
+func (t *TodoServiceImpl) FindById(ctx context.Context, todoId int) (data.TodoResponse, error) {
+	todo, err := t.TodoRepository.FindById(ctx, todoId)
+	if err != nil {
+		return data.TodoResponse{}, err
+	}
+
+	return newTodoResponse(todo), nil
+}
+
+// Update replaces the todo, including its tags.
+func (t *TodoServiceImpl) Update(ctx context.Context, todoId int, todo data.TodoRequest) error {
+	todoModel, err := t.newTodo(ctx, todo)
+	if err != nil {
+		return err
+	}
+
+	current, err := t.TodoRepository.FindById(ctx, todoId)
+	if err != nil {
+		return err
+	}
+
+	todoModel.Id = current.Id
+	return t.TodoRepository.Update(ctx, todoModel)
+}
+
+func (t *TodoServiceImpl) Delete(ctx context.Context, todoId int) error {
+	return t.TodoRepository.Delete(ctx, todoId)
+}
+
+// newTodo validates the request and turns it into a model whose Tags carry only the ids to link.
+func (t *TodoServiceImpl) newTodo(ctx context.Context, todo data.TodoRequest) (model.Todo, error) {
+	if err := t.Validate.Struct(todo); err != nil {
+		return model.Todo{}, helper.ErrFailedValidationWrap(err)
+	}
+
+	missing, err := t.TodoRepository.FindMissingTags(ctx, todo.TagIds)
+	if err != nil {
+		return model.Todo{}, err
+	}
+	if len(missing) > 0 {
+		return model.Todo{}, helper.ErrFailedValidationWrap(fmt.Errorf("tags %v do not exist", missing))
+	}
+
+	status := todo.Status
+	if status == "" {
+		status = data.TodoOpen
+	}
+	todoModel := model.Todo{
+		Title:    todo.Title,
+		Status:   status,
+		Priority: todo.Priority,
+		DueDate:  todo.DueDate,
+	}
+	seen := map[int]bool{}
+	for _, id := range todo.TagIds {
+		if !seen[id] {
+			seen[id] = true
+			todoModel.Tags = append(todoModel.Tags, model.Tags{Id: id})
+		}
+	}
+	return todoModel, nil
+}
+
+func newTodoResponse(todo model.Todo) data.TodoResponse {
+	response := data.TodoResponse{
+		Id:        todo.Id,
+		Title:     todo.Title,
+		Status:    todo.Status,
+		Priority:  todo.Priority,
+		Tags:      []data.TagResponse{},
+		CreatedAt: formatTimestamp(todo.CreatedAt),
+		UpdatedAt: formatTimestamp(todo.UpdatedAt),
+	}
+	if todo.DueDate != nil {
+		response.DueDate = formatTimestamp(*todo.DueDate)
+	}
+	for _, tag := range todo.Tags {
+		response.Tags = append(response.Tags, newTagResponse(tag))
+	}
+	return response
+}
@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/api/service"
+	"go-gin-project/data"
+	"go-gin-project/helper"
+	"go-gin-project/model"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTodoRepository struct {
+	mock.Mock
+}
+
+func (m *MockTodoRepository) Save(_ context.Context, todo model.Todo) (model.Todo, error) {
+	args := m.Called(todo)
+	saved, ok := args.Get(0).(model.Todo)
+	if !ok {
+		return model.Todo{}, errors.New("invalid type assertion for Save")
+	}
+	return saved, args.Error(1)
+}
+
+func (m *MockTodoRepository) FindAll(_ context.Context, query data.TodoQuery) ([]model.Todo, int64, error) {
+	args := m.Called(query)
+	todos, ok := args.Get(0).([]model.Todo)
+	if !ok {
+		return nil, 0, errors.New("invalid type assertion for FindAll")
+	}
+	return todos, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTodoRepository) FindById(_ context.Context, todoId int) (model.Todo, error) {
+	args := m.Called(todoId)
+	todo, ok := args.Get(0).(model.Todo)
+	if !ok {
+		return model.Todo{}, errors.New("invalid type assertion for FindById")
+	}
+	return todo, args.Error(1)
+}
+
+func (m *MockTodoRepository) Update(_ context.Context, todo model.Todo) error {
+	args := m.Called(todo)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) Delete(_ context.Context, todoId int) error {
+	args := m.Called(todoId)
+	return args.Error(0)
+}
+
+func (m *MockTodoRepository) FindMissingTags(_ context.Context, tagIds []int) ([]int, error) {
+	args := m.Called(tagIds)
+	missing, _ := args.Get(0).([]int)
+	return missing, args.Error(1)
+}
+
+func setupTodoTest() (*MockTodoRepository, service.TodoService) {
+	mockRepo := new(MockTodoRepository)
+	todoService := service.NewTodoServiceImpl(mockRepo, validator.New())
+	return mockRepo, todoService
+}
+
+func TestTodoService(t *testing.T) {
+	t.Run("should create an open todo with deduplicated tags", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+		expected := model.Todo{Title: "Write docs", Status: data.TodoOpen, Tags: []model.Tags{{Id: 2}, {Id: 1}}}
+		mockRepo.On("FindMissingTags", []int{2, 1, 2}).Return(nil, nil).Once()
+		mockRepo.On("Save", expected).Return(model.Todo{Id: 7}, nil).Once()
+		mockRepo.On("FindById", 7).Return(model.Todo{Id: 7, Title: "Write docs", Status: data.TodoOpen,
+			Tags: []model.Tags{{Id: 1, Name: "docs"}, {Id: 2, Name: "go"}}}, nil).Once()
+
+		todo, err := todoService.Create(ctx, data.TodoRequest{Title: "Write docs", TagIds: []int{2, 1, 2}})
+		assert.Nil(t, err)
+		assert.Equal(t, 7, todo.Id)
+		assert.Equal(t, data.TodoOpen, todo.Status)
+		assert.Equal(t, "docs", todo.Tags[0].Name)
+		assert.Empty(t, todo.DueDate)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject tags that do not exist", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+		mockRepo.On("FindMissingTags", []int{1, 9}).Return([]int{9}, nil).Once()
+
+		_, err := todoService.Create(ctx, data.TodoRequest{Title: "Write docs", TagIds: []int{1, 9}})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		assert.Contains(t, err.Error(), "[9]")
+		mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("should reject an invalid status", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+
+		_, err := todoService.Create(ctx, data.TodoRequest{Title: "Write docs", Status: "blocked"})
+		assert.ErrorIs(t, err, helper.ErrFailedValidation)
+		mockRepo.AssertNotCalled(t, "FindMissingTags", mock.Anything)
+	})
+
+	t.Run("should normalize the page and list todos with empty tags", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+		mockRepo.On("FindAll", data.TodoQuery{Page: 1, PageSize: 20, TagIds: []int{1}}).
+			Return([]model.Todo{{Id: 1, Title: "Write docs"}}, int64(1), nil).Once()
+
+		todos, meta, err := todoService.FindAll(ctx, data.TodoQuery{TagIds: []int{1}})
+		assert.Nil(t, err)
+		assert.Len(t, todos, 1)
+		assert.NotNil(t, todos[0].Tags)
+		assert.Equal(t, data.NewPageMeta(1, 20, 1), meta)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not update a missing todo", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+		mockRepo.On("FindMissingTags", []int(nil)).Return(nil, nil).Once()
+		mockRepo.On("FindById", 9).Return(model.Todo{}, helper.ErrNotFound).Once()
+
+		err := todoService.Update(ctx, 9, data.TodoRequest{Title: "Write docs"})
+		assert.ErrorIs(t, err, helper.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("should update the todo by its stored id", func(t *testing.T) {
+		mockRepo, todoService := setupTodoTest()
+		mockRepo.On("FindMissingTags", []int{3}).Return(nil, nil).Once()
+		mockRepo.On("FindById", 2).Return(model.Todo{Id: 2}, nil).Once()
+		mockRepo.On("Update", model.Todo{Id: 2, Title: "Fix bug", Status: data.TodoDone, Priority: 3,
+			Tags: []model.Tags{{Id: 3}}}).Return(nil).Once()
+
+		err := todoService.Update(ctx, 2, data.TodoRequest{Title: "Fix bug", Status: data.TodoDone, Priority: 3, TagIds: []int{3}})
+		assert.Nil(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -11,15 +11,73 @@ import (
 	"go-gin-project/api/repository"
 	"go-gin-project/api/service"
 	"go-gin-project/config"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/router"
 )
 
 // Injectors from injection.go:
 
-func InitializeTagsController() *controller.TagsController {
-	db := config.DatabaseConnection()
-	tagsRepository := repository.NewTagsRepositoryImpl(db)
+func InitializeApp(cfg *config.Config) (*App, error) {
+	databaseConfig := cfg.Database
+	metricsMetrics := metrics.New()
+	tracingConfig := cfg.Tracing
+	tracerProvider, err := config.NewTracerProvider(tracingConfig)
+	if err != nil {
+		return nil, err
+	}
+	db, err := config.NewDatabase(databaseConfig, metricsMetrics, tracerProvider)
+	if err != nil {
+		return nil, err
+	}
+	tagsRepository := repository.NewInstrumentedTagsRepository(db, metricsMetrics, tracerProvider)
 	validate := config.NewValidator()
-	tagsService := service.NewTagsServiceImpl(tagsRepository, validate)
+	tagsConfig := cfg.Tags
+	signer, err := config.NewCursorSigner(tagsConfig)
+	if err != nil {
+		return nil, err
+	}
+	trashRetention := config.NewTrashRetention(tagsConfig)
+	tagsService := service.NewTracedTagsService(tagsRepository, validate, signer, trashRetention, tracerProvider)
 	tagsController := controller.NewTagsController(tagsService)
-	return tagsController
+	todoRepository := repository.NewTodoRepositoryImpl(db)
+	todoService := service.NewTodoServiceImpl(todoRepository, validate)
+	todoController := controller.NewTodoController(todoService)
+	apiKeysRepository := repository.NewApiKeysRepositoryImpl(db)
+	apiKeysService := service.NewApiKeysServiceImpl(apiKeysRepository, validate)
+	apiKeysController := controller.NewApiKeysController(apiKeysService)
+	healthConfig := cfg.Health
+	registry := config.NewHealthRegistry(healthConfig, db)
+	healthController := controller.NewHealthController(registry)
+	controllers := router.Controllers{
+		Tags:    tagsController,
+		Todo:    todoController,
+		ApiKeys: apiKeysController,
+		Health:  healthController,
+	}
+	authConfig := cfg.Auth
+	verifier, err := config.NewTokenVerifier(authConfig)
+	if err != nil {
+		return nil, err
+	}
+	store := config.NewRateLimitStore()
+	rateLimitConfig := cfg.RateLimit
+	rateLimits := router.RateLimits{
+		Store:  store,
+		Config: rateLimitConfig,
+	}
+	trustedProxies := cfg.TrustedProxies
+	logConfig := cfg.Log
+	logger := config.NewLogger(logConfig)
+	engine, err := router.NewRouter(controllers, verifier, apiKeysService, metricsMetrics, tracerProvider, rateLimits, trustedProxies, logger)
+	if err != nil {
+		return nil, err
+	}
+	serverServer := config.NewServer(cfg, engine, registry)
+	app := &App{
+		Server:         serverServer,
+		DB:             db,
+		TracerProvider: tracerProvider,
+		Logger:         logger,
+	}
+	return app, nil
 }
@@ -1,44 +1,62 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"go-gin-project/api"
 	"go-gin-project/config"
+	"go-gin-project/helper/tracing"
 	"go-gin-project/model"
-	"go-gin-project/router"
-	"log"
-	"net/http"
+	"log/slog"
 	"os"
-	"time"
-
-	"github.com/joho/godotenv"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
 
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		os.Exit(1)
 	}
-
-	db := config.DatabaseConnection()
-	if db == nil {
-		log.Fatal("Database connection failed")
+	app, err := api.InitializeApp(cfg)
+	if err != nil {
+		slog.Error("startup failed", "error", err)
+		os.Exit(1)
 	}
+	// The log package and code without a request logger write through the configured logger too.
+	slog.SetDefault(app.Logger)
 
-	if err := model.Migration(db); err != nil {
-		log.Fatal("Database migration failed:", err)
-	}
-
-	router := router.SetupRouter()
-
-	server := &http.Server{
-		Addr:           ":" + os.Getenv("PORT"),
-		Handler:        router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	if err := model.Migration(app.DB); err != nil {
+		slog.Error("database migration failed", "error", err)
+		os.Exit(1)
 	}
 
-	log.Println("Server running on port", os.Getenv("PORT"))
-	if err := server.ListenAndServe(); err != nil {
-		log.Fatal("Server error:", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// A second signal during the shutdown kills the process right away.
+		<-ctx.Done()
+		stop()
+	}()
+
+	server := app.Server
+	server.OnShutdown(func(ctx context.Context) error {
+		sqlDB, err := app.DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	})
+	server.OnShutdown(func(ctx context.Context) error {
+		return tracing.Shutdown(ctx, app.TracerProvider)
+	})
+
+	slog.Info("server running", "port", cfg.Port)
+	if err := server.ListenAndServe(ctx); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
 	}
+	slog.Info("server stopped")
 }
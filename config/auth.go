@@ -0,0 +1,38 @@
+package config
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"go-gin-project/helper/auth"
+	"maps"
+	"time"
+)
+
+// NewTokenVerifier builds the bearer token verifier from the JWT secret (HS256) and the public key or JWKS file
+// (RS256) in config. The audience and issuer are checked when set; the leeway allows for clock skew.
+func NewTokenVerifier(config AuthConfig) (*auth.Verifier, error) {
+	options := auth.Options{
+		Secret:     []byte(config.Secret),
+		PublicKeys: map[string]*rsa.PublicKey{},
+		Audience:   config.Audience,
+		Issuer:     config.Issuer,
+		Leeway:     time.Duration(config.Leeway),
+	}
+
+	if config.PublicKeyFile != "" {
+		keys, err := auth.LoadPublicKey(config.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+		maps.Copy(options.PublicKeys, keys)
+	}
+	if config.JwksFile != "" {
+		keys, err := auth.LoadJWKS(config.JwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT_JWKS_FILE: %w", err)
+		}
+		maps.Copy(options.PublicKeys, keys)
+	}
+
+	return auth.NewVerifier(options)
+}
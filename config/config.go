@@ -0,0 +1,151 @@
+package config
+
+import (
+	"go-gin-project/helper/ratelimit"
+	"strings"
+	"time"
+)
+
+// Config is the configuration of the service. Load fills it from, in increasing order of precedence: the defaults
+// below, the YAML or TOML file named by CONFIG_FILE, a .env file in the working directory, and the environment.
+// Each setting lists the variable it is read from in its env tag and its key in configuration files in its yaml
+// and toml tags.
+type Config struct {
+	Port int `yaml:"port" toml:"port" env:"PORT" validate:"min=1,max=65535"`
+	// TrustedProxies are the addresses and CIDR ranges whose X-Forwarded-For and X-Real-IP headers are believed
+	// when telling clients apart. By default no proxy is trusted and the peer address is the client.
+	TrustedProxies TrustedProxies  `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES" validate:"dive,cidr|ip"`
+	Database       DatabaseConfig  `yaml:"database" toml:"database"`
+	Log            LogConfig       `yaml:"log" toml:"log"`
+	Auth           AuthConfig      `yaml:"auth" toml:"auth"`
+	Tags           TagsConfig      `yaml:"tags" toml:"tags"`
+	RateLimit      RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Tracing        TracingConfig   `yaml:"tracing" toml:"tracing"`
+	Health         HealthConfig    `yaml:"health" toml:"health"`
+	Shutdown       ShutdownConfig  `yaml:"shutdown" toml:"shutdown"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"DBHOST" validate:"required"`
+	Port     int    `yaml:"port" toml:"port" env:"DBPORT" validate:"min=1,max=65535"`
+	User     string `yaml:"user" toml:"user" env:"DBUSER" validate:"required"`
+	Password string `yaml:"password" toml:"password" env:"DBPASSWORD" validate:"required"`
+	Name     string `yaml:"name" toml:"name" env:"DBNAME" validate:"required"`
+	// SlowQueryThreshold is how long a query may take before it is logged as a warning; 0 turns this off.
+	SlowQueryThreshold Duration `yaml:"slow_query_threshold" toml:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD" validate:"gte=0"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" validate:"oneof=debug info warn error"`
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" validate:"oneof=json text"`
+}
+
+// AuthConfig verifies bearer tokens with Secret (HS256) or the RSA keys in PublicKeyFile and JwksFile (RS256);
+// at least one of them is needed.
+type AuthConfig struct {
+	Secret        string   `yaml:"jwt_secret" toml:"jwt_secret" env:"JWT_SECRET" validate:"required_without_all=PublicKeyFile JwksFile"`
+	PublicKeyFile string   `yaml:"jwt_public_key_file" toml:"jwt_public_key_file" env:"JWT_PUBLIC_KEY_FILE" validate:"omitempty,file"`
+	JwksFile      string   `yaml:"jwt_jwks_file" toml:"jwt_jwks_file" env:"JWT_JWKS_FILE" validate:"omitempty,file"`
+	Audience      string   `yaml:"jwt_audience" toml:"jwt_audience" env:"JWT_AUDIENCE"`
+	Issuer        string   `yaml:"jwt_issuer" toml:"jwt_issuer" env:"JWT_ISSUER"`
+	Leeway        Duration `yaml:"jwt_leeway" toml:"jwt_leeway" env:"JWT_LEEWAY" validate:"gte=0"`
+}
+
+type TagsConfig struct {
+	// CursorSecret signs pagination cursors; without it a random key is used and cursors die with the process.
+	CursorSecret   string   `yaml:"cursor_secret" toml:"cursor_secret" env:"CURSOR_SECRET"`
+	TrashRetention Duration `yaml:"trash_retention" toml:"trash_retention" env:"TRASH_RETENTION" validate:"gte=0"`
+}
+
+// RateLimitConfig holds the limit of every route group. Groups override Default and are read from
+// RATE_LIMIT_<GROUP> variables, such as RATE_LIMIT_TAG, or from the groups table of a configuration file.
+type RateLimitConfig struct {
+	Default ratelimit.Limit            `yaml:"default" toml:"default" env:"RATE_LIMIT"`
+	Groups  map[string]ratelimit.Limit `yaml:"groups" toml:"groups"`
+}
+
+// rateLimitGroupPrefix starts the variables that set the limit of a single route group.
+const rateLimitGroupPrefix = "RATE_LIMIT_"
+
+// For returns the limit of a route group such as "tag" or "api-key".
+func (c RateLimitConfig) For(group string) ratelimit.Limit {
+	if limit, ok := c.Groups[rateLimitGroupKey(group)]; ok {
+		return limit
+	}
+	return c.Default
+}
+
+// rateLimitGroupKey lets "api-key", "api_key" and "API_KEY" name the same group.
+func rateLimitGroupKey(group string) string {
+	return strings.ToLower(strings.ReplaceAll(group, "-", "_"))
+}
+
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" toml:"exporter" env:"TRACE_EXPORTER" validate:"oneof=otlp stdout none"`
+	ServiceName string `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" validate:"required"`
+	// OtlpEndpoint is the base URL of the collector the otlp exporter sends spans to.
+	OtlpEndpoint string `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" validate:"omitempty,url"`
+}
+
+type HealthConfig struct {
+	CheckTimeout Duration `yaml:"check_timeout" toml:"check_timeout" env:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"`
+}
+
+// ShutdownConfig keeps /readyz failing for Delay before the listener closes and then gives in-flight requests
+// Timeout to finish.
+type ShutdownConfig struct {
+	Delay   Duration `yaml:"delay" toml:"delay" env:"SHUTDOWN_DELAY" validate:"gte=0"`
+	Timeout Duration `yaml:"timeout" toml:"timeout" env:"SHUTDOWN_TIMEOUT" validate:"gte=0"`
+}
+
+// Default returns the configuration used for every setting no source sets.
+func Default() Config {
+	return Config{
+		Port: 8080,
+		Database: DatabaseConfig{
+			Port:               5432,
+			SlowQueryThreshold: Duration(200 * time.Millisecond),
+		},
+		Log: LogConfig{Level: "info", Format: "json"},
+		Tags: TagsConfig{
+			TrashRetention: Duration(30 * 24 * time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Default: ratelimit.Limit{Requests: 600, Per: time.Minute, Burst: 100},
+			Groups:  map[string]ratelimit.Limit{},
+		},
+		Tracing:  TracingConfig{Exporter: "none", ServiceName: "go-gin-project"},
+		Health:   HealthConfig{CheckTimeout: Duration(2 * time.Second)},
+		Shutdown: ShutdownConfig{Delay: Duration(5 * time.Second), Timeout: Duration(20 * time.Second)},
+	}
+}
+
+// TrustedProxies is a list of IP addresses and CIDR ranges, written as text separated by commas.
+type TrustedProxies []string
+
+func (p *TrustedProxies) UnmarshalText(text []byte) error {
+	proxies := TrustedProxies{}
+	for _, proxy := range strings.Split(string(text), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	*p = proxies
+	return nil
+}
+
+// Duration is a time.Duration written as text such as "30s" or "1h30m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
@@ -0,0 +1,130 @@
+package config_test
+
+import (
+	"go-gin-project/config"
+	"go-gin-project/helper/ratelimit"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// required are the settings without a default.
+var required = []string{"DBHOST=db", "DBUSER=app", "DBPASSWORD=secret", "DBNAME=tags", "JWT_SECRET=jwt"}
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("should fill in defaults without a .env file", func(t *testing.T) {
+		cfg, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"), required)
+		assert.Nil(t, err)
+		assert.Equal(t, 8080, cfg.Port)
+		assert.Equal(t, "db", cfg.Database.Host)
+		assert.Equal(t, 5432, cfg.Database.Port)
+		assert.Equal(t, config.Duration(200*time.Millisecond), cfg.Database.SlowQueryThreshold)
+		assert.Equal(t, "none", cfg.Tracing.Exporter)
+		assert.Equal(t, ratelimit.Limit{Requests: 600, Per: time.Minute, Burst: 100}, cfg.RateLimit.For("tag"))
+		assert.Empty(t, cfg.TrustedProxies)
+	})
+
+	t.Run("should read trusted proxies as a comma separated list", func(t *testing.T) {
+		cfg, err := config.LoadFrom("", append([]string{"TRUSTED_PROXIES=10.0.0.0/8, 192.0.2.1,"}, required...))
+		assert.Nil(t, err)
+		assert.Equal(t, config.TrustedProxies{"10.0.0.0/8", "192.0.2.1"}, cfg.TrustedProxies)
+	})
+
+	t.Run("should let the environment override .env, which overrides the file", func(t *testing.T) {
+		file := writeFile(t, "config.yaml", `
+port: 9000
+log:
+  level: debug
+  format: text
+shutdown:
+  delay: 1s
+rate_limit:
+  groups:
+    api-key: 10/1m
+`)
+		dotEnv := writeFile(t, ".env", "CONFIG_FILE="+file+"\nPORT=9100\nLOG_LEVEL=warn\nJWT_LEEWAY=\n")
+		environ := append([]string{"PORT=9200", "RATE_LIMIT=60/1m", "RATE_LIMIT_TAG=5/1s:2"}, required...)
+
+		cfg, err := config.LoadFrom(dotEnv, environ)
+		assert.Nil(t, err)
+		assert.Equal(t, 9200, cfg.Port)
+		assert.Equal(t, "warn", cfg.Log.Level)
+		assert.Equal(t, "text", cfg.Log.Format)
+		assert.Equal(t, config.Duration(time.Second), cfg.Shutdown.Delay)
+		assert.Equal(t, config.Duration(0), cfg.Auth.Leeway)
+		assert.Equal(t, ratelimit.Limit{Requests: 5, Per: time.Second, Burst: 2}, cfg.RateLimit.For("tag"))
+		assert.Equal(t, ratelimit.Limit{Requests: 10, Per: time.Minute, Burst: 10}, cfg.RateLimit.For("api-key"))
+		assert.Equal(t, ratelimit.Limit{Requests: 60, Per: time.Minute, Burst: 60}, cfg.RateLimit.For("todo"))
+	})
+
+	t.Run("should read a TOML file", func(t *testing.T) {
+		file := writeFile(t, "config.toml", `
+port = 9000
+
+[database]
+host = "toml-db"
+slow_query_threshold = "1s"
+`)
+		cfg, err := config.LoadFrom("", append([]string{"CONFIG_FILE=" + file, "DBHOST="}, required[1:]...))
+		assert.Nil(t, err)
+		assert.Equal(t, 9000, cfg.Port)
+		assert.Equal(t, "toml-db", cfg.Database.Host)
+		assert.Equal(t, config.Duration(time.Second), cfg.Database.SlowQueryThreshold)
+	})
+
+	t.Run("should report every problem at once", func(t *testing.T) {
+		file := writeFile(t, "config.yaml", "prot: 9000\n")
+		environ := []string{
+			"CONFIG_FILE=" + file,
+			"DBHOST=db",
+			"PORT=eighty",
+			"DBPORT=70000",
+			"LOG_LEVEL=verbose",
+			"SHUTDOWN_TIMEOUT=soon",
+			"RATE_LIMIT_TAG=lots",
+			"TRACE_EXPORTER=zipkin",
+			"JWT_JWKS_FILE=/missing/jwks.json",
+			"TRUSTED_PROXIES=10.0.0.0/8,proxy.local",
+		}
+
+		_, err := config.LoadFrom("", environ)
+		assert.NotNil(t, err)
+		for _, problem := range []string{
+			"CONFIG_FILE: yaml: unmarshal errors:\n  line 1: field prot not found",
+			`PORT: "eighty" is not a whole number`,
+			"SHUTDOWN_TIMEOUT: time: invalid duration",
+			`RATE_LIMIT_TAG: rate limit "lots" is not <requests>/<period>`,
+			"DBPORT must be at most 65535",
+			"DBUSER is required",
+			"DBPASSWORD is required",
+			"DBNAME is required",
+			"LOG_LEVEL must be one of debug, info, warn, error",
+			"TRACE_EXPORTER must be one of otlp, stdout, none",
+			"JWT_JWKS_FILE must name an existing file",
+			"TRUSTED_PROXIES[1] must be an IP address or CIDR range",
+		} {
+			assert.Contains(t, err.Error(), problem)
+		}
+		assert.NotContains(t, err.Error(), "DBHOST")
+	})
+
+	t.Run("should need a way to verify tokens", func(t *testing.T) {
+		_, err := config.LoadFrom("", required[:4])
+		assert.EqualError(t, err, "JWT_SECRET is required unless JWT_PUBLIC_KEY_FILE or JWT_JWKS_FILE is set")
+	})
+
+	t.Run("should reject an unknown file format", func(t *testing.T) {
+		file := writeFile(t, "config.json", "{}")
+		_, err := config.LoadFrom("", append([]string{"CONFIG_FILE=" + file}, required...))
+		assert.ErrorContains(t, err, "is not a .yaml, .yml or .toml file")
+	})
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"go-gin-project/helper/cursor"
+	"log/slog"
+)
+
+func NewCursorSigner(config TagsConfig) (*cursor.Signer, error) {
+	if config.CursorSecret == "" {
+		slog.Warn("CURSOR_SECRET is not set, using a random key; cursors will not survive a restart")
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate cursor key: %w", err)
+		}
+		return cursor.NewSigner(key), nil
+	}
+	return cursor.NewSigner([]byte(config.CursorSecret)), nil
+}
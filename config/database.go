@@ -2,39 +2,45 @@ package config
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"go-gin-project/helper/logging"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/helper/tracing"
+	"log/slog"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var (
-	db *gorm.DB
-)
-
-func DatabaseConnection() *gorm.DB {
-	var err error
-
-	host := os.Getenv("DBHOST")
-	user := os.Getenv("DBUSER")
-	password := os.Getenv("DBPASSWORD")
-	dbname := os.Getenv("DBNAME")
-	port := os.Getenv("DBPORT")
-
-	if host == "" || user == "" || password == "" || dbname == "" || port == "" {
-		log.Fatal("One or more required environment variables are not set")
+// NewDatabase opens the connection pool every repository shares. Its statistics are exported through metrics and
+// its queries traced through tracerProvider.
+func NewDatabase(config DatabaseConfig, metrics *metrics.Metrics, tracerProvider trace.TracerProvider) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC",
+		config.Host, config.User, config.Password, config.Name, config.Port)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+		Logger: logging.NewGormLogger(time.Duration(config.SlowQueryThreshold)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
-		host, user, password, dbname, port)
+	if err := db.Use(tracing.NewGormPlugin(tracerProvider)); err != nil {
+		return nil, fmt.Errorf("failed to trace database queries: %w", err)
+	}
 
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = metrics.RegisterDB(config.Name, sqlDB)
+	}
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		return nil, fmt.Errorf("failed to export database pool metrics: %w", err)
 	}
 
-	log.Println("Database connected successfully")
-
-	return db
+	slog.Info("database connected", "host", config.Host, "port", config.Port, "database", config.Name)
+	return db, nil
 }
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"go-gin-project/helper/health"
+	"go-gin-project/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NewHealthRegistry returns the readiness checks of the process: "database" pings Postgres and "migrations" looks
+// for tables and columns the schema still lacks. Each check gives up after the configured timeout.
+func NewHealthRegistry(config HealthConfig, db *gorm.DB) *health.Registry {
+	registry := health.NewRegistry(time.Duration(config.CheckTimeout))
+
+	registry.Register("database", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	registry.Register("migrations", func(ctx context.Context) error {
+		pending, err := model.PendingMigrations(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("schema is missing %s", strings.Join(pending, ", "))
+		}
+		return nil
+	})
+	return registry
+}
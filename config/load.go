@@ -0,0 +1,242 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"go-gin-project/helper/ratelimit"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileVariable names the variable that points at an optional YAML (.yaml, .yml) or TOML (.toml) file.
+const ConfigFileVariable = "CONFIG_FILE"
+
+// Load reads the configuration as described on Config, from the .env file in the working directory and the
+// environment of the process. A missing .env file is fine; containers usually set the environment directly.
+func Load() (*Config, error) {
+	return LoadFrom(".env", os.Environ())
+}
+
+// LoadFrom reads the configuration from the given .env file and environment, a list of KEY=value entries such
+// as os.Environ returns. It validates the result and reports every problem at once, joined into one error.
+func LoadFrom(dotEnvPath string, environ []string) (*Config, error) {
+	variables, err := readDotEnv(dotEnvPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range environ {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			variables[name] = value
+		}
+	}
+
+	var errs []error
+	config := Default()
+	if path := variables[ConfigFileVariable]; path != "" {
+		if err := readFile(path, &config); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ConfigFileVariable, err))
+		}
+	}
+	applyVariables(reflect.ValueOf(&config).Elem(), variables, &errs)
+	applyRateLimitGroups(&config.RateLimit, variables, &errs)
+	errs = append(errs, validateConfig(&config)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &config, nil
+}
+
+func readDotEnv(path string) (map[string]string, error) {
+	variables, err := godotenv.Read(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return variables, nil
+}
+
+// readFile decodes a configuration file over config, rejecting keys Config does not have.
+func readFile(path string, config *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		err = decoder.Decode(config)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(content)).DisallowUnknownFields().Decode(config)
+	default:
+		err = fmt.Errorf("%s is not a .yaml, .yml or .toml file", path)
+	}
+	return err
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// applyVariables sets every field with an env tag whose variable is set, walking nested structs. Variables set to
+// an empty string count as unset.
+func applyVariables(value reflect.Value, variables map[string]string, errs *[]error) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		structField := value.Type().Field(i)
+		name, tagged := structField.Tag.Lookup("env")
+		if !tagged {
+			if field.Kind() == reflect.Struct {
+				applyVariables(field, variables, errs)
+			}
+			continue
+		}
+
+		raw := variables[name]
+		if raw == "" {
+			continue
+		}
+		if err := setField(field, raw); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+}
+
+func setField(field reflect.Value, raw string) error {
+	if field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", raw)
+		}
+		field.SetInt(int64(number))
+	case reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", raw)
+		}
+		field.SetBool(flag)
+	default:
+		return fmt.Errorf("cannot read a %s from a variable", field.Type())
+	}
+	return nil
+}
+
+// applyRateLimitGroups reads the RATE_LIMIT_<GROUP> variables into the limits of their groups.
+func applyRateLimitGroups(config *RateLimitConfig, variables map[string]string, errs *[]error) {
+	if config.Groups == nil {
+		config.Groups = map[string]ratelimit.Limit{}
+	}
+	normalized := make(map[string]ratelimit.Limit, len(config.Groups))
+	for group, limit := range config.Groups {
+		normalized[rateLimitGroupKey(group)] = limit
+	}
+	config.Groups = normalized
+
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group, ok := strings.CutPrefix(name, rateLimitGroupPrefix)
+		if !ok || group == "" || variables[name] == "" {
+			continue
+		}
+		var limit ratelimit.Limit
+		if err := limit.UnmarshalText([]byte(variables[name])); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		config.Groups[rateLimitGroupKey(group)] = limit
+	}
+}
+
+// validateConfig checks the rules in the validate tags, naming each setting after its variable.
+func validateConfig(config *Config) []error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		if name := field.Tag.Get("env"); name != "" {
+			return name
+		}
+		return field.Name
+	})
+
+	err := validate.Struct(config)
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		if err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
+	errs := make([]error, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		errs = append(errs, fmt.Errorf("%s %s", fieldError.Field(), describe(fieldError)))
+	}
+	return errs
+}
+
+func describe(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "required_without_all":
+		others := strings.Fields(fieldError.Param())
+		for i, other := range others {
+			others[i] = siblingVariable(fieldError, other)
+		}
+		return "is required unless " + strings.Join(others, " or ") + " is set"
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fieldError.Param()), ", ")
+	case "min", "gte":
+		return "must be at least " + fieldError.Param()
+	case "max", "lte":
+		return "must be at most " + fieldError.Param()
+	case "gt":
+		return "must be more than " + fieldError.Param()
+	case "file":
+		return "must name an existing file"
+	case "url":
+		return "must be a URL"
+	case "cidr|ip":
+		return "must be an IP address or CIDR range"
+	}
+	return "is invalid (" + fieldError.Tag() + ")"
+}
+
+// siblingVariable returns the variable of another field of the struct fieldError belongs to.
+func siblingVariable(fieldError validator.FieldError, name string) string {
+	parent := reflect.TypeOf(Config{})
+	path := strings.Split(fieldError.StructNamespace(), ".")
+	for _, step := range path[1 : len(path)-1] {
+		field, _ := parent.FieldByName(step)
+		parent = field.Type
+	}
+	if field, ok := parent.FieldByName(name); ok && field.Tag.Get("env") != "" {
+		return field.Tag.Get("env")
+	}
+	return name
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"go-gin-project/helper/logging"
+	"log/slog"
+	"os"
+)
+
+// NewLogger builds the logger set in config. Load has already checked the level and format.
+func NewLogger(config LogConfig) *slog.Logger {
+	level, _ := logging.ParseLevel(config.Level)
+	logger, err := logging.New(os.Stdout, level, config.Format)
+	if err != nil {
+		logger = slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+	}
+	return logger
+}
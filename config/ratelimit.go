@@ -0,0 +1,11 @@
+package config
+
+import (
+	"go-gin-project/helper/ratelimit"
+)
+
+// NewRateLimitStore returns the store rate limit buckets are kept in. Buckets live in memory, so each replica
+// limits on its own.
+func NewRateLimitStore() ratelimit.Store {
+	return ratelimit.NewMemoryStore()
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// TrashRetention is how long a deleted tag stays in the trash before a purge may drop it for good.
+type TrashRetention time.Duration
+
+func NewTrashRetention(config TagsConfig) TrashRetention {
+	return TrashRetention(config.TrashRetention)
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"go-gin-project/helper/health"
+	"go-gin-project/helper/server"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// NewServer serves handler on the configured port, shutting down as set in config.Shutdown.
+func NewServer(config *Config, handler http.Handler, registry *health.Registry) *server.Server {
+	httpServer := &http.Server{
+		Addr:           ":" + strconv.Itoa(config.Port),
+		Handler:        handler,
+		ReadTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+	return server.New(httpServer, registry, time.Duration(config.Shutdown.Delay), time.Duration(config.Shutdown.Timeout))
+}
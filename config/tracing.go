@@ -0,0 +1,28 @@
+package config
+
+import (
+	"context"
+	"go-gin-project/helper/tracing"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracerProvider returns the tracer provider of the process, exporting spans as set in config. The provider and
+// the W3C trace context propagator are also installed globally for libraries that look there.
+func NewTracerProvider(config TracingConfig) (trace.TracerProvider, error) {
+	provider, err := tracing.NewProvider(context.Background(), tracing.Options{
+		Exporter:     config.Exporter,
+		ServiceName:  config.ServiceName,
+		OtlpEndpoint: config.OtlpEndpoint,
+		Out:          os.Stdout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider, nil
+}
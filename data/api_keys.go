@@ -0,0 +1,28 @@
+package data
+
+import "time"
+
+type ApiKeyRequest struct {
+	Name      string     `validate:"required,min=1,max=255" json:"name"`
+	Scopes    []string   `validate:"max=20,dive,min=1,max=64" json:"scopes"`
+	TenantId  string     `json:"tenant_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ApiKeyResponse struct {
+	Id         int      `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	TenantId   string   `json:"tenant_id,omitempty"`
+	ExpiresAt  string   `json:"expires_at,omitempty"`
+	LastUsedAt string   `json:"last_used_at,omitempty"`
+	RevokedAt  string   `json:"revoked_at,omitempty"`
+	CreatedAt  string   `json:"created_at,omitempty"`
+}
+
+// CreatedApiKeyResponse carries the plaintext key. It is only returned when the key is created.
+type CreatedApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
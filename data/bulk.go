@@ -0,0 +1,35 @@
+package data
+
+const MaxBulkItems = 1000
+
+// BulkTagUpdateRequest replaces the writable fields of tag Id, like a single update does.
+type BulkTagUpdateRequest struct {
+	Id      int `validate:"required,min=1" json:"id"`
+	Version int `validate:"omitempty,min=1" json:"version"`
+	TagRequest
+}
+
+type BulkResult struct {
+	Index   int    `json:"index"`
+	Id      int    `json:"id,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type BulkMeta struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+func NewBulkMeta(results []BulkResult) BulkMeta {
+	meta := BulkMeta{Total: len(results)}
+	for _, result := range results {
+		if result.Success {
+			meta.Succeeded++
+		} else {
+			meta.Failed++
+		}
+	}
+	return meta
+}
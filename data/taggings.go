@@ -0,0 +1,27 @@
+package data
+
+// ResourceRef names a tagged resource by the type and id its owner uses for it.
+type ResourceRef struct {
+	ResourceType string `uri:"resourceType" validate:"required,max=100" json:"resource_type"`
+	ResourceId   string `uri:"resourceId" validate:"required,max=255" json:"resource_id"`
+}
+
+type ResourceResponse struct {
+	ResourceType string `json:"resource_type"`
+	ResourceId   string `json:"resource_id"`
+	TaggedAt     string `json:"tagged_at,omitempty"`
+}
+
+type TaggingQuery struct {
+	Page         int    `form:"page" validate:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" validate:"omitempty,min=1"`
+	ResourceType string `form:"resource_type" validate:"omitempty,max=100"`
+}
+
+func (q *TaggingQuery) Normalize() {
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+}
+
+func (q TaggingQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
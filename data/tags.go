@@ -1,10 +1,158 @@
 package data
 
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
 type TagRequest struct {
-	Name string `validate:"required,min=4,max=200" json:"name"`
+	Name        string         `validate:"required,min=4,max=200" json:"name"`
+	ParentId    *int           `validate:"omitempty,min=1" json:"parent_id,omitempty"`
+	Description string         `validate:"max=1000" json:"description,omitempty"`
+	Color       string         `validate:"omitempty,hexcolor" json:"color,omitempty"`
+	Icon        string         `validate:"omitempty,max=64" json:"icon,omitempty"`
+	Attributes  map[string]any `validate:"omitempty,max=50,dive,keys,min=1,max=64,endkeys" json:"attributes,omitempty"`
+}
+
+// TagPatchDocument is the document a patch of a tag applies to. Unlike TagRequest it carries every writable
+// field, null when unset, so JSON Patch operations can test, replace and remove any of them.
+type TagPatchDocument struct {
+	Name        string         `json:"name"`
+	ParentId    *int           `json:"parent_id"`
+	Description *string        `json:"description"`
+	Color       *string        `json:"color"`
+	Icon        *string        `json:"icon"`
+	Attributes  map[string]any `json:"attributes"`
+}
+
+type AliasRequest struct {
+	Name string `validate:"required,min=1,max=200" json:"name"`
+}
+
+type MergeTagRequest struct {
+	SourceIds []int `validate:"required,min=1,max=100,dive,min=1" json:"source_ids"`
+}
+
+type MoveTagRequest struct {
+	ParentId *int `validate:"omitempty,min=1" json:"parent_id"`
 }
 
+// Strategies for the child tags of a deleted tag.
+const (
+	DeleteReject   = "reject"
+	DeleteCascade  = "cascade"
+	DeleteReparent = "reparent"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
 type TagResponse struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
-}
\ No newline at end of file
+	Id          int            `json:"id"`
+	Name        string         `json:"name"`
+	ParentId    *int           `json:"parent_id,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Color       string         `json:"color,omitempty"`
+	Icon        string         `json:"icon,omitempty"`
+	Attributes  map[string]any `json:"attributes,omitempty"`
+	Aliases     []string       `json:"aliases,omitempty"`
+	Version     int            `json:"version"`
+	CreatedAt   string         `json:"created_at,omitempty"`
+	UpdatedAt   string         `json:"updated_at,omitempty"`
+	DeletedAt   string         `json:"deleted_at,omitempty"`
+}
+
+// TagTreeNode is a tag with its child tags nested below it.
+type TagTreeNode struct {
+	TagResponse
+	Children []TagTreeNode `json:"children"`
+}
+
+// Bounds of a tag tree: MaxTreeDepth levels at most, also the default, and MaxTreeNodes tags at most.
+const (
+	MaxTreeDepth = 20
+	MaxTreeNodes = 1000
+)
+
+// TagTreeQuery limits the tree to MaxDepth levels, the roots being the first.
+type TagTreeQuery struct {
+	MaxDepth int `form:"max_depth" validate:"omitempty,min=1,max=20"`
+}
+
+// Normalize defaults MaxDepth to MaxTreeDepth.
+func (q *TagTreeQuery) Normalize() {
+	if q.MaxDepth == 0 {
+		q.MaxDepth = MaxTreeDepth
+	}
+}
+
+// TagTreeMeta describes a tree response. Truncated is set when tags below MaxDepth or beyond MaxTreeNodes were
+// left out.
+type TagTreeMeta struct {
+	MaxDepth  int  `json:"max_depth"`
+	Nodes     int  `json:"nodes"`
+	Truncated bool `json:"truncated"`
+}
+
+type PurgeResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+type TagQuery struct {
+	Page         int                 `form:"page" validate:"omitempty,min=1"`
+	PageSize     int                 `form:"page_size" validate:"omitempty,min=1"`
+	Sort         string              `form:"sort" validate:"omitempty,oneof=id -id name -name"`
+	NameContains string              `form:"name_contains" validate:"omitempty,max=200"`
+	NamePrefix   string              `form:"name_prefix" validate:"omitempty,max=200"`
+	CreatedAfter time.Time           `form:"created_after"`
+	UpdatedSince time.Time           `form:"updated_since"`
+	Pagination   string              `form:"pagination" validate:"omitempty,oneof=offset cursor"`
+	Cursor       string              `form:"cursor"`
+	Attributes   map[string][]string `form:"-" validate:"max=10,dive,keys,min=1,max=64,excludes=\",endkeys,max=20"`
+}
+
+const attributeFilterPrefix = "attr."
+
+// AttributeFilters collects the attr.<key>=<value> parameters of a query string for TagQuery.Attributes.
+// A tag matches when, for every key, its attribute equals one of the listed values.
+func AttributeFilters(values url.Values) map[string][]string {
+	var filters map[string][]string
+	for name, list := range values {
+		key, ok := strings.CutPrefix(name, attributeFilterPrefix)
+		if !ok {
+			continue
+		}
+		if filters == nil {
+			filters = map[string][]string{}
+		}
+		filters[key] = list
+	}
+	return filters
+}
+
+// UsesCursor reports whether the listing should use keyset pagination instead of page offsets.
+func (q TagQuery) UsesCursor() bool {
+	return q.Pagination == "cursor" || q.Cursor != ""
+}
+
+// TagCursor is the position a keyset page starts from. It travels to clients as a signed, opaque token.
+// Sort and Filters pin it to the listing it was issued for; Filters is a digest of the query's filters.
+type TagCursor struct {
+	Id       int    `json:"i"`
+	Name     string `json:"n,omitempty"`
+	Sort     string `json:"s,omitempty"`
+	Filters  string `json:"f,omitempty"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Normalize fills in default paging values and caps the page size at MaxPageSize.
+func (q *TagQuery) Normalize() {
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+}
+
+func (q TagQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
@@ -0,0 +1,51 @@
+package data
+
+import "time"
+
+// Todo statuses.
+const (
+	TodoOpen       = "open"
+	TodoInProgress = "in_progress"
+	TodoDone       = "done"
+)
+
+type TodoRequest struct {
+	Title    string     `validate:"required,min=1,max=255" json:"title"`
+	Status   string     `validate:"omitempty,oneof=open in_progress done" json:"status"`
+	Priority int        `validate:"min=0,max=3" json:"priority"`
+	DueDate  *time.Time `json:"due_date"`
+	TagIds   []int      `validate:"max=50,dive,min=1" json:"tag_ids"`
+}
+
+type TodoResponse struct {
+	Id        int           `json:"id"`
+	Title     string        `json:"title"`
+	Status    string        `json:"status"`
+	Priority  int           `json:"priority"`
+	DueDate   string        `json:"due_date,omitempty"`
+	Tags      []TagResponse `json:"tags"`
+	CreatedAt string        `json:"created_at,omitempty"`
+	UpdatedAt string        `json:"updated_at,omitempty"`
+}
+
+type TodoQuery struct {
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"page_size" validate:"omitempty,min=1"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=id -id due_date -due_date priority -priority"`
+	Status   string `form:"status" validate:"omitempty,oneof=open in_progress done"`
+	TagIds   []int  `form:"tag_id" validate:"max=20,dive,min=1"`
+	TagMatch string `form:"tag_match" validate:"omitempty,oneof=any all"`
+}
+
+// MatchesAllTags reports whether a todo must carry every requested tag rather than at least one of them.
+func (q TodoQuery) MatchesAllTags() bool {
+	return q.TagMatch == "all"
+}
+
+func (q *TodoQuery) Normalize() {
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+}
+
+func (q TodoQuery) Offset() int {
+	return (q.Page - 1) * q.PageSize
+}
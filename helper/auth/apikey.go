@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidApiKey = errors.New("invalid api key")
+
+// ApiKeyPrefix starts every API key, so leaked keys are easy to recognise.
+const ApiKeyPrefix = "tk_"
+
+// NewApiKey returns a random key of the form tk_<id>_<secret> together with its prefix (tk_<id>), which identifies
+// the key, and the hash of the secret, which is all that is stored of it.
+func NewApiKey() (key, prefix, hash string, err error) {
+	id := make([]byte, 4)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", "", err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	prefix = ApiKeyPrefix + hex.EncodeToString(id)
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	return prefix + "_" + encoded, prefix, HashApiKeySecret(encoded), nil
+}
+
+// ParseApiKey splits a key into its prefix and secret.
+func ParseApiKey(key string) (prefix, secret string, err error) {
+	rest, ok := strings.CutPrefix(key, ApiKeyPrefix)
+	if !ok {
+		return "", "", ErrInvalidApiKey
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != 8 || secret == "" {
+		return "", "", ErrInvalidApiKey
+	}
+	return ApiKeyPrefix + id, secret, nil
+}
+
+// HashApiKeySecret hashes a secret for storage. Secrets are random, so a fast hash is enough.
+func HashApiKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// ApiKeySecretMatches compares a secret with a stored hash in constant time.
+func ApiKeySecretMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashApiKeySecret(secret)), []byte(hash)) == 1
+}
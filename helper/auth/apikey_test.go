@@ -0,0 +1,38 @@
+package auth_test
+
+import (
+	"go-gin-project/helper/auth"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKey(t *testing.T) {
+	t.Run("should generate keys that parse back to their prefix and secret", func(t *testing.T) {
+		key, prefix, hash, err := auth.NewApiKey()
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(key, prefix+"_"))
+		assert.Len(t, prefix, len(auth.ApiKeyPrefix)+8)
+		assert.NotContains(t, hash, strings.TrimPrefix(key, prefix+"_"))
+
+		parsedPrefix, secret, err := auth.ParseApiKey(key)
+		assert.Nil(t, err)
+		assert.Equal(t, prefix, parsedPrefix)
+		assert.True(t, auth.ApiKeySecretMatches(secret, hash))
+		assert.False(t, auth.ApiKeySecretMatches(secret+"x", hash))
+	})
+
+	t.Run("should generate distinct keys", func(t *testing.T) {
+		first, _, _, _ := auth.NewApiKey()
+		second, _, _, _ := auth.NewApiKey()
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("should reject malformed keys", func(t *testing.T) {
+		for _, key := range []string{"", "tk_", "tk_0123abcd", "tk_0123abcd_", "tk_0123_secret", "xx_0123abcd_secret"} {
+			_, _, err := auth.ParseApiKey(key)
+			assert.ErrorIs(t, err, auth.ErrInvalidApiKey, key)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package auth
+
+import "slices"
+
+// Scopes a caller can hold. A scope also grants the scopes it implies.
+const (
+	ScopeTagsRead     = "tags:read"
+	ScopeTagsWrite    = "tags:write"
+	ScopeTagsAdmin    = "tags:admin"
+	ScopeTodosRead    = "todos:read"
+	ScopeTodosWrite   = "todos:write"
+	ScopeApiKeysAdmin = "api-keys:admin"
+	// ScopeTenantsAny lets a credential without a tenant of its own pick one with the tenant header. No role
+	// grants it; it has to be named explicitly.
+	ScopeTenantsAny = "tenants:any"
+)
+
+// scopeImplies lists, for each scope, the narrower scopes it includes. Every known scope has an entry.
+var scopeImplies = map[string][]string{
+	ScopeTagsRead:     nil,
+	ScopeTagsWrite:    {ScopeTagsRead},
+	ScopeTagsAdmin:    {ScopeTagsWrite},
+	ScopeTodosRead:    nil,
+	ScopeTodosWrite:   {ScopeTodosRead},
+	ScopeApiKeysAdmin: nil,
+	ScopeTenantsAny:   nil,
+}
+
+// roleScopes maps the roles a token may name in its roles claim to the scopes they grant.
+var roleScopes = map[string][]string{
+	"viewer": {ScopeTagsRead, ScopeTodosRead},
+	"editor": {ScopeTagsWrite, ScopeTodosWrite},
+	"admin":  {ScopeTagsAdmin, ScopeTodosWrite, ScopeApiKeysAdmin},
+}
+
+// KnownScope reports whether scope is one of the declared scopes.
+func KnownScope(scope string) bool {
+	_, ok := scopeImplies[scope]
+	return ok
+}
+
+// RoleScopes returns the scopes granted by the named roles; unknown roles grant nothing.
+func RoleScopes(roles []string) []string {
+	scopes := []string{}
+	for _, role := range roles {
+		scopes = append(scopes, roleScopes[role]...)
+	}
+	return scopes
+}
+
+// Grants reports whether the granted scopes include required, directly or through a broader scope.
+func Grants(granted []string, required string) bool {
+	for _, scope := range granted {
+		if scope == required || slices.ContainsFunc(scopeImplies[scope], func(implied string) bool {
+			return Grants([]string{implied}, required)
+		}) {
+			return true
+		}
+	}
+	return false
+}
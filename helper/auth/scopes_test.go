@@ -0,0 +1,38 @@
+package auth_test
+
+import (
+	"go-gin-project/helper/auth"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrants(t *testing.T) {
+	t.Run("should grant a scope and the scopes it implies", func(t *testing.T) {
+		admin := []string{auth.ScopeTagsAdmin}
+		assert.True(t, auth.Grants(admin, auth.ScopeTagsAdmin))
+		assert.True(t, auth.Grants(admin, auth.ScopeTagsWrite))
+		assert.True(t, auth.Grants(admin, auth.ScopeTagsRead))
+		assert.False(t, auth.Grants(admin, auth.ScopeApiKeysAdmin))
+	})
+
+	t.Run("should not grant broader scopes", func(t *testing.T) {
+		assert.False(t, auth.Grants([]string{auth.ScopeTagsRead}, auth.ScopeTagsWrite))
+		assert.False(t, auth.Grants([]string{auth.ScopeTagsWrite}, auth.ScopeTagsAdmin))
+		assert.False(t, auth.Grants(nil, auth.ScopeTagsRead))
+		assert.False(t, auth.Grants([]string{"tags:*"}, auth.ScopeTagsRead))
+	})
+
+	t.Run("should map roles to scopes", func(t *testing.T) {
+		assert.Equal(t, []string{auth.ScopeTagsRead, auth.ScopeTodosRead}, auth.RoleScopes([]string{"viewer", "unknown"}))
+		assert.True(t, auth.Grants(auth.RoleScopes([]string{"admin"}), auth.ScopeApiKeysAdmin))
+		assert.True(t, auth.Grants(auth.RoleScopes([]string{"editor"}), auth.ScopeTodosRead))
+		assert.False(t, auth.Grants(auth.RoleScopes([]string{"viewer"}), auth.ScopeTodosWrite))
+		assert.Empty(t, auth.RoleScopes(nil))
+	})
+
+	t.Run("should know the declared scopes only", func(t *testing.T) {
+		assert.True(t, auth.KnownScope(auth.ScopeTagsWrite))
+		assert.False(t, auth.KnownScope("tags:delete"))
+	})
+}
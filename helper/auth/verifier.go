@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Options configure a Verifier. At least one of Secret and PublicKeys is required.
+type Options struct {
+	// Secret verifies HS256 tokens.
+	Secret []byte
+	// PublicKeys verify RS256 tokens, by the kid in the token header. A token without a kid is accepted
+	// when only one key is configured.
+	PublicKeys map[string]*rsa.PublicKey
+	Audience   string
+	Issuer     string
+	// Leeway tolerates clock skew on exp and nbf.
+	Leeway time.Duration
+}
+
+// Verifier checks bearer tokens: the signature, exp and nbf, and aud and iss when they are configured.
+type Verifier struct {
+	options Options
+	parser  *jwt.Parser
+}
+
+func NewVerifier(options Options) (*Verifier, error) {
+	methods := []string{}
+	if len(options.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(options.PublicKeys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("auth: a secret or a public key is required")
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(options.Leeway),
+	}
+	if options.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audience))
+	}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	return &Verifier{options: options, parser: jwt.NewParser(parserOptions...)}, nil
+}
+
+// Verify returns the claims of a valid token. Every failure is reported as ErrInvalidToken wrapping the cause.
+func (v *Verifier) Verify(raw string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(raw, claims, v.key); err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	return claims, nil
+}
+
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.options.Secret, nil
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.options.PublicKeys[kid]; ok {
+			return key, nil
+		}
+		if kid == "" && len(v.options.PublicKeys) == 1 {
+			for _, key := range v.options.PublicKeys {
+				return key, nil
+			}
+		}
+		return nil, errors.New("unknown signing key")
+	default:
+		return nil, errors.New("unexpected signing method")
+	}
+}
@@ -0,0 +1,143 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"go-gin-project/helper/auth"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var secret = []byte("test-secret")
+
+func sign(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	assert.Nil(t, err)
+	return token
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub": "user-1",
+		"aud": "tags-api",
+		"iss": "https://issuer.example",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	verifier, err := auth.NewVerifier(auth.Options{Secret: secret, Audience: "tags-api", Issuer: "https://issuer.example"})
+	assert.Nil(t, err)
+
+	t.Run("should accept a valid token", func(t *testing.T) {
+		claims, err := verifier.Verify(sign(t, validClaims()))
+		assert.Nil(t, err)
+		subject, _ := claims.GetSubject()
+		assert.Equal(t, "user-1", subject)
+	})
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		expired := validClaims()
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+		early := validClaims()
+		early["nbf"] = time.Now().Add(time.Hour).Unix()
+		otherAudience := validClaims()
+		otherAudience["aud"] = "billing-api"
+		otherIssuer := validClaims()
+		otherIssuer["iss"] = "https://evil.example"
+		noExpiry := validClaims()
+		delete(noExpiry, "exp")
+		wrongSignature, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("other-secret"))
+		unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+		tokens := map[string]string{
+			"expired":         sign(t, expired),
+			"not yet valid":   sign(t, early),
+			"wrong audience":  sign(t, otherAudience),
+			"wrong issuer":    sign(t, otherIssuer),
+			"without exp":     sign(t, noExpiry),
+			"wrong signature": wrongSignature,
+			"unsigned":        unsigned,
+			"malformed":       "not.a.token",
+			"empty":           "",
+		}
+		for name, token := range tokens {
+			_, err := verifier.Verify(token)
+			assert.ErrorIs(t, err, auth.ErrInvalidToken, name)
+		}
+	})
+
+	t.Run("should tolerate clock skew within the leeway", func(t *testing.T) {
+		lenient, _ := auth.NewVerifier(auth.Options{Secret: secret, Leeway: time.Minute})
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+
+		_, err := lenient.Verify(sign(t, claims))
+		assert.Nil(t, err)
+	})
+
+	t.Run("should require a key", func(t *testing.T) {
+		_, err := auth.NewVerifier(auth.Options{})
+		assert.NotNil(t, err)
+	})
+}
+
+func writeJWKS(t *testing.T, kid string, key *rsa.PublicKey) string {
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "EC", "kid": "ignored"},
+		{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		},
+	}}
+	content, _ := json.Marshal(set)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+func TestVerifyRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	keys, err := auth.LoadJWKS(writeJWKS(t, "key-1", &key.PublicKey))
+	assert.Nil(t, err)
+	verifier, err := auth.NewVerifier(auth.Options{PublicKeys: keys})
+	assert.Nil(t, err)
+
+	signRS256 := func(kid string, signer *rsa.PrivateKey) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, _ := token.SignedString(signer)
+		return signed
+	}
+
+	t.Run("should accept a token signed by a key of the set", func(t *testing.T) {
+		_, err := verifier.Verify(signRS256("key-1", key))
+		assert.Nil(t, err)
+		_, err = verifier.Verify(signRS256("", key))
+		assert.Nil(t, err)
+	})
+
+	t.Run("should reject unknown keys and other algorithms", func(t *testing.T) {
+		other, _ := rsa.GenerateKey(rand.Reader, 2048)
+		_, err := verifier.Verify(signRS256("key-1", other))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+		_, err = verifier.Verify(signRS256("key-2", key))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+		_, err = verifier.Verify(sign(t, validClaims()))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+}
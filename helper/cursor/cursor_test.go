@@ -0,0 +1,54 @@
+package cursor_test
+
+import (
+	"go-gin-project/helper/cursor"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type position struct {
+	Id   int    `json:"i"`
+	Name string `json:"n"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	signer := cursor.NewSigner([]byte("secret"))
+
+	t.Run("should round trip a value", func(t *testing.T) {
+		token, err := signer.Encode(position{Id: 7, Name: "golang"})
+		assert.Nil(t, err)
+
+		var decoded position
+		err = signer.Decode(token, &decoded)
+		assert.Nil(t, err)
+		assert.Equal(t, position{Id: 7, Name: "golang"}, decoded)
+	})
+
+	t.Run("should reject a tampered payload", func(t *testing.T) {
+		token, _ := signer.Encode(position{Id: 7})
+		forged, _ := signer.Encode(position{Id: 8})
+		_, signature, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(forged, ".")
+
+		var decoded position
+		err := signer.Decode(payload+"."+signature, &decoded)
+		assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+	})
+
+	t.Run("should reject a token signed with another secret", func(t *testing.T) {
+		token, _ := cursor.NewSigner([]byte("other")).Encode(position{Id: 7})
+
+		var decoded position
+		err := signer.Decode(token, &decoded)
+		assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+	})
+
+	t.Run("should reject malformed tokens", func(t *testing.T) {
+		var decoded position
+		assert.ErrorIs(t, signer.Decode("", &decoded), cursor.ErrInvalidCursor)
+		assert.ErrorIs(t, signer.Decode("not-a-cursor", &decoded), cursor.ErrInvalidCursor)
+		assert.ErrorIs(t, signer.Decode("abc.!!!", &decoded), cursor.ErrInvalidCursor)
+	})
+}
@@ -3,12 +3,55 @@ package helper
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	ErrNotFound             = errors.New("resource not found")
+	ErrConflict             = errors.New("resource already exists")
+	ErrPreconditionFailed   = errors.New("resource has been modified")
+	ErrHasChildren          = errors.New("resource has children")
+	ErrMissingTenant        = errors.New("tenant is missing")
+	ErrForbidden            = errors.New("operation not permitted")
 	ErrFailedValidation     = errors.New("validation failed")
 	ErrFailedValidationWrap = func(err error) error {
 		return fmt.Errorf("%w: %v", ErrFailedValidation, err)
 	}
+	ErrInvalidPatch     = errors.New("patch cannot be applied")
+	ErrInvalidPatchWrap = func(err error) error {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
 )
+
+// BatchItemError reports which item of a batch made the whole batch fail.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchItemErrors reports every item that made a batch fail, in item order.
+type BatchItemErrors []*BatchItemError
+
+func (e BatchItemErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, itemErr := range e {
+		messages[i] = itemErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e BatchItemErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, itemErr := range e {
+		errs[i] = itemErr
+	}
+	return errs
+}
@@ -0,0 +1,66 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/helper/health"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("should be up when every check passes", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second)
+		registry.Register("database", func(ctx context.Context) error { return nil })
+		registry.Register("cache", func(ctx context.Context) error { return nil })
+
+		report := registry.Run(context.Background())
+		assert.True(t, report.Healthy())
+		assert.Equal(t, []string{"cache", "database"}, registry.Names())
+		assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+		assert.NotEmpty(t, report.Checks["database"].Duration)
+	})
+
+	t.Run("should be down when a check fails", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second)
+		registry.Register("database", func(ctx context.Context) error { return errors.New("connection refused") })
+		registry.Register("cache", func(ctx context.Context) error { return nil })
+
+		report := registry.Run(context.Background())
+		assert.False(t, report.Healthy())
+		assert.Equal(t, health.Result{Status: health.StatusDown, Error: "connection refused", Duration: report.Checks["database"].Duration}, report.Checks["database"])
+		assert.Equal(t, health.StatusUp, report.Checks["cache"].Status)
+	})
+
+	t.Run("should give up on a slow check", func(t *testing.T) {
+		registry := health.NewRegistry(20 * time.Millisecond)
+		release := make(chan struct{})
+		defer close(release)
+		registry.Register("stuck", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+		start := time.Now()
+		report := registry.Run(context.Background())
+		assert.Less(t, time.Since(start), time.Second)
+		assert.False(t, report.Healthy())
+		assert.Equal(t, "check timed out", report.Checks["stuck"].Error)
+	})
+
+	t.Run("should replace a check registered under the same name", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second)
+		registry.Register("database", func(ctx context.Context) error { return errors.New("down") })
+		registry.Register("database", func(ctx context.Context) error { return nil })
+
+		assert.True(t, registry.Run(context.Background()).Healthy())
+	})
+
+	t.Run("should be up without checks", func(t *testing.T) {
+		report := health.NewRegistry(time.Second).Run(context.Background())
+		assert.True(t, report.Healthy())
+		assert.Empty(t, report.Checks)
+	})
+}
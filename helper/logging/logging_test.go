@@ -0,0 +1,134 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-gin-project/helper/logging"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// records decodes the JSON lines written by a logger.
+func records(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	entries := []map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := map[string]any{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should write JSON or text", func(t *testing.T) {
+		var buffer bytes.Buffer
+		logger, err := logging.New(&buffer, slog.LevelInfo, "json")
+		assert.Nil(t, err)
+		logger.Info("hello", "tag_id", 1)
+		assert.Equal(t, "hello", records(t, &buffer)[0]["msg"])
+
+		buffer.Reset()
+		logger, err = logging.New(&buffer, slog.LevelInfo, "text")
+		assert.Nil(t, err)
+		logger.Info("hello", "tag_id", 1)
+		assert.Contains(t, buffer.String(), "msg=hello tag_id=1")
+
+		_, err = logging.New(&buffer, slog.LevelInfo, "xml")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should parse levels", func(t *testing.T) {
+		level, err := logging.ParseLevel("debug")
+		assert.Nil(t, err)
+		assert.Equal(t, slog.LevelDebug, level)
+
+		level, err = logging.ParseLevel("")
+		assert.Nil(t, err)
+		assert.Equal(t, slog.LevelInfo, level)
+
+		_, err = logging.ParseLevel("loud")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should carry the logger in the context", func(t *testing.T) {
+		logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+
+		assert.Same(t, logger, logging.FromContext(logging.NewContext(context.Background(), logger)))
+		assert.Same(t, slog.Default(), logging.FromContext(context.Background()))
+	})
+}
+
+type row struct {
+	Id   int
+	Name string
+}
+
+func setupGormLogger(t *testing.T, level slog.Level, slowThreshold time.Duration) (*gorm.DB, context.Context, *bytes.Buffer) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logging.NewGormLogger(slowThreshold)})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&row{}))
+
+	var buffer bytes.Buffer
+	logger, _ := logging.New(&buffer, level, "json")
+	ctx := logging.NewContext(context.Background(), logger.With("request_id", "req-1"))
+	return db, ctx, &buffer
+}
+
+func TestGormLogger(t *testing.T) {
+	t.Run("should log queries at debug level without their parameters", func(t *testing.T) {
+		db, ctx, buffer := setupGormLogger(t, slog.LevelDebug, 0)
+
+		db.WithContext(ctx).Create(&row{Name: "secret-value"})
+
+		entries := records(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "query", entries[0]["msg"])
+		assert.Equal(t, "DEBUG", entries[0]["level"])
+		assert.Equal(t, "req-1", entries[0]["request_id"])
+		assert.Equal(t, float64(1), entries[0]["rows"])
+		assert.Contains(t, entries[0]["sql"], "INSERT INTO")
+		assert.NotContains(t, buffer.String(), "secret-value")
+	})
+
+	t.Run("should skip queries below the logger's level", func(t *testing.T) {
+		db, ctx, buffer := setupGormLogger(t, slog.LevelInfo, 0)
+
+		db.WithContext(ctx).Create(&row{Name: "tag"})
+
+		assert.Empty(t, buffer.String())
+	})
+
+	t.Run("should warn about slow queries", func(t *testing.T) {
+		db, ctx, buffer := setupGormLogger(t, slog.LevelInfo, time.Nanosecond)
+
+		db.WithContext(ctx).Find(&[]row{})
+
+		entries := records(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "slow query", entries[0]["msg"])
+		assert.Equal(t, "WARN", entries[0]["level"])
+	})
+
+	t.Run("should report failed queries but not missing records", func(t *testing.T) {
+		db, ctx, buffer := setupGormLogger(t, slog.LevelInfo, 0)
+
+		db.WithContext(ctx).First(&row{}, 42)
+		assert.Empty(t, buffer.String())
+
+		db.WithContext(ctx).Table("missing").Find(&[]row{})
+		entries := records(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "query failed", entries[0]["msg"])
+		assert.Contains(t, entries[0]["error"], "no such table")
+	})
+}
@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"go-gin-project/helper"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors of the service in its own registry, so tests can build as many as they need.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests           *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	inFlight           *prometheus.GaugeVec
+	repositoryDuration *prometheus.HistogramVec
+	repositoryErrors   *prometheus.CounterVec
+}
+
+// New registers the HTTP and repository collectors together with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests served, by route template and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to serve HTTP requests, by route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests being served, by route template.",
+		}, []string{"method", "route"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "repository_call_duration_seconds",
+			Help:    "Time taken by repository calls, by repository and method.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "repository_call_errors_total",
+			Help: "Repository calls that returned an error, by repository, method and kind of error.",
+		}, []string{"repository", "method", "kind"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.requestDuration, m.inFlight, m.repositoryDuration, m.repositoryErrors,
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// RegisterDB exports the connection pool statistics of db under the given database name.
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// StartRequest counts a request as in flight until the returned function is called with its status.
+func (m *Metrics) StartRequest(method, route string) func(status int) {
+	start := time.Now()
+	inFlight := m.inFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+	return func(status int) {
+		inFlight.Dec()
+		code := strconv.Itoa(status)
+		m.requests.WithLabelValues(method, route, code).Inc()
+		m.requestDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveRepositoryCall records the duration of a repository call and, when it failed, the kind of error.
+func (m *Metrics) ObserveRepositoryCall(repository, method string, elapsed time.Duration, err error) {
+	m.repositoryDuration.WithLabelValues(repository, method).Observe(elapsed.Seconds())
+	if err != nil {
+		m.repositoryErrors.WithLabelValues(repository, method, errorKind(err)).Inc()
+	}
+}
+
+// errorKind tells expected outcomes such as a missing record apart from failures of the database.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, helper.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, helper.ErrConflict):
+		return "conflict"
+	case errors.Is(err, helper.ErrPreconditionFailed):
+		return "precondition_failed"
+	case errors.Is(err, helper.ErrHasChildren):
+		return "has_children"
+	case errors.Is(err, helper.ErrFailedValidation):
+		return "validation"
+	}
+	return "internal"
+}
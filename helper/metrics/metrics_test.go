@@ -0,0 +1,69 @@
+package metrics_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-gin-project/helper"
+	"go-gin-project/helper/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("should count requests and track them while in flight", func(t *testing.T) {
+		m := metrics.New()
+
+		done := m.StartRequest("GET", "/tag/:tagId")
+		assert.Contains(t, scrape(t, m), `http_requests_in_flight{method="GET",route="/tag/:tagId"} 1`)
+
+		done(http.StatusOK)
+		body := scrape(t, m)
+		assert.Contains(t, body, `http_requests_in_flight{method="GET",route="/tag/:tagId"} 0`)
+		assert.Contains(t, body, `http_requests_total{method="GET",route="/tag/:tagId",status="200"} 1`)
+		assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/tag/:tagId",status="200"} 1`)
+	})
+
+	t.Run("should time repository calls and count errors by kind", func(t *testing.T) {
+		m := metrics.New()
+
+		m.ObserveRepositoryCall("tags", "FindById", time.Millisecond, nil)
+		m.ObserveRepositoryCall("tags", "FindById", time.Millisecond, helper.ErrNotFound)
+		m.ObserveRepositoryCall("tags", "Save", time.Millisecond, fmt.Errorf("save: %w", helper.ErrConflict))
+		m.ObserveRepositoryCall("tags", "Save", time.Millisecond, errors.New("connection reset"))
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `repository_call_duration_seconds_count{method="FindById",repository="tags"} 2`)
+		assert.Contains(t, body, `repository_call_errors_total{kind="not_found",method="FindById",repository="tags"} 1`)
+		assert.Contains(t, body, `repository_call_errors_total{kind="conflict",method="Save",repository="tags"} 1`)
+		assert.Contains(t, body, `repository_call_errors_total{kind="internal",method="Save",repository="tags"} 1`)
+	})
+
+	t.Run("should export connection pool statistics", func(t *testing.T) {
+		m := metrics.New()
+		db, err := sql.Open("sqlite3", ":memory:")
+		assert.Nil(t, err)
+		defer db.Close()
+
+		assert.Nil(t, m.RegisterDB("tags", db))
+		assert.Contains(t, scrape(t, m), `go_sql_max_open_connections{db_name="tags"} 0`)
+		assert.NotNil(t, m.RegisterDB("tags", db))
+	})
+
+	t.Run("should export runtime metrics", func(t *testing.T) {
+		assert.Contains(t, scrape(t, metrics.New()), "go_goroutines")
+	})
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket: Burst requests at once, refilled at Requests per Per.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// Disabled reports whether the limit lets every request through.
+func (l Limit) Disabled() bool {
+	return l.Requests <= 0 || l.Per <= 0
+}
+
+// interval is the time it takes to refill one token.
+func (l Limit) interval() time.Duration {
+	return l.Per / time.Duration(l.Requests)
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s:%d", l.Requests, l.Per, l.Burst)
+}
+
+// ParseLimit reads a limit written as <requests>/<period>[:<burst>], such as "60/1m" or "60/1m:10".
+// The burst defaults to the number of requests. "off" disables limiting.
+func ParseLimit(value string) (Limit, error) {
+	if value == "off" {
+		return Limit{}, nil
+	}
+	rate, burst, hasBurst := strings.Cut(value, ":")
+	count, period, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q is not <requests>/<period>", value)
+	}
+
+	limit := Limit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(count); err != nil || limit.Requests < 1 {
+		return Limit{}, fmt.Errorf("rate limit %q needs a positive number of requests", value)
+	}
+	if limit.Per, err = time.ParseDuration(period); err != nil || limit.Per <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q needs a positive period", value)
+	}
+	limit.Burst = limit.Requests
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst < 1 {
+			return Limit{}, fmt.Errorf("rate limit %q needs a positive burst", value)
+		}
+	}
+	return limit, nil
+}
+
+// UnmarshalText reads a limit in the format of ParseLimit, so limits can be loaded from configuration.
+func (l *Limit) UnmarshalText(text []byte) error {
+	limit, err := ParseLimit(string(text))
+	if err != nil {
+		return err
+	}
+	*l = limit
+	return nil
+}
+
+// Result describes the bucket after taking a token from it.
+type Result struct {
+	Allowed bool
+	// Remaining is the number of requests that can be made right away.
+	Remaining int
+	// RetryAfter is the time until the next request is allowed; zero when Allowed.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// Store keeps token buckets by key. MemoryStore serves a single process; replicas that should share their limits
+// need a Store backed by a shared database.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
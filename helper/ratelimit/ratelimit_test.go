@@ -0,0 +1,112 @@
+package ratelimit_test
+
+import (
+	"context"
+	"go-gin-project/helper/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	t.Run("should parse limits with and without a burst", func(t *testing.T) {
+		limit, err := ratelimit.ParseLimit("60/1m")
+		assert.Nil(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 60, Per: time.Minute, Burst: 60}, limit)
+
+		limit, err = ratelimit.ParseLimit("10/1s:3")
+		assert.Nil(t, err)
+		assert.Equal(t, ratelimit.Limit{Requests: 10, Per: time.Second, Burst: 3}, limit)
+
+		limit, err = ratelimit.ParseLimit("off")
+		assert.Nil(t, err)
+		assert.True(t, limit.Disabled())
+	})
+
+	t.Run("should reject malformed limits", func(t *testing.T) {
+		for _, value := range []string{"", "60", "0/1m", "x/1m", "60/0s", "60/minute", "60/1m:0", "60/1m:x"} {
+			_, err := ratelimit.ParseLimit(value)
+			assert.NotNil(t, err, value)
+		}
+	})
+}
+
+// clock is a manual time source for MemoryStore.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func setupStore() (*ratelimit.MemoryStore, *clock) {
+	store := ratelimit.NewMemoryStore()
+	c := &clock{now: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	store.Clock = c.Now
+	return store, c
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 1, Per: time.Second, Burst: 2}
+
+	t.Run("should allow a burst and then ask to retry", func(t *testing.T) {
+		store, _ := setupStore()
+
+		first, _ := store.Take(ctx, "client", limit)
+		assert.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1, Reset: time.Second}, first)
+		second, _ := store.Take(ctx, "client", limit)
+		assert.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0, Reset: 2 * time.Second}, second)
+
+		rejected, _ := store.Take(ctx, "client", limit)
+		assert.False(t, rejected.Allowed)
+		assert.Equal(t, time.Second, rejected.RetryAfter)
+	})
+
+	t.Run("should refill over time up to the burst", func(t *testing.T) {
+		store, c := setupStore()
+		store.Take(ctx, "client", limit)
+		store.Take(ctx, "client", limit)
+
+		c.now = c.now.Add(500 * time.Millisecond)
+		rejected, _ := store.Take(ctx, "client", limit)
+		assert.False(t, rejected.Allowed)
+		assert.Equal(t, 500*time.Millisecond, rejected.RetryAfter)
+
+		c.now = c.now.Add(time.Hour)
+		allowed, _ := store.Take(ctx, "client", limit)
+		assert.True(t, allowed.Allowed)
+		assert.Equal(t, 1, allowed.Remaining)
+	})
+
+	t.Run("should keep clients apart", func(t *testing.T) {
+		store, _ := setupStore()
+		store.Take(ctx, "client", limit)
+		store.Take(ctx, "client", limit)
+
+		other, _ := store.Take(ctx, "other", limit)
+		assert.True(t, other.Allowed)
+	})
+
+	t.Run("should drop refilled buckets", func(t *testing.T) {
+		store, c := setupStore()
+		store.Take(ctx, "client", limit)
+		store.Take(ctx, "other", limit)
+		assert.Equal(t, 2, store.Len())
+
+		c.now = c.now.Add(time.Hour)
+		store.Take(ctx, "third", limit)
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("should allow everything when disabled", func(t *testing.T) {
+		store, _ := setupStore()
+		for range 10 {
+			result, _ := store.Take(ctx, "client", ratelimit.Limit{})
+			assert.True(t, result.Allowed)
+		}
+		assert.Equal(t, 0, store.Len())
+	})
+}
@@ -0,0 +1,103 @@
+package responsejson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StrongETag quotes value as a strong entity tag. Use it when value changes with every change to the representation,
+// such as a row version.
+func StrongETag(value string) string {
+	return `"` + value + `"`
+}
+
+// WeakETag hashes the JSON encoding of payload into a weak entity tag. It suits listings, where an equal tag
+// means the client already holds an equivalent page rather than a byte-identical one.
+func WeakETag(payload interface{}) (string, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// NotModified sets the ETag and Last-Modified validators on the response and answers 304 Not Modified without
+// a body when the request's If-None-Match or If-Modified-Since shows the client copy is current. It returns true
+// when the 304 has been written and the handler must stop. Empty validators are skipped.
+func NotModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		ctx.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+		return false
+	}
+
+	fresh := false
+	if ifNoneMatch := ctx.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		fresh = etag != "" && etagListMatches(ifNoneMatch, etag)
+	} else if ifModifiedSince := ctx.GetHeader("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		fresh = err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+
+	if fresh {
+		ctx.AbortWithStatus(http.StatusNotModified)
+	}
+	return fresh
+}
+
+// etagListMatches applies the weak comparison used by If-None-Match to a comma separated list of entity tags.
+func etagListMatches(list string, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// callerHeaders are the request headers that pick the credential and tenant a response is built for.
+const callerHeaders = "Authorization, X-API-Key, X-Tenant-ID"
+
+// markPrivate keeps shared caches from handing a response, or a 304 confirming one, to another caller or tenant.
+func markPrivate(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "private")
+	ctx.Writer.Header().Add("Vary", callerHeaders)
+}
+
+// ConditionalSuccess is Success for a single readable resource that honours conditional GET. The resource is
+// taken to depend on the caller and tenant, so the response is marked private.
+func ConditionalSuccess(ctx *gin.Context, data interface{}, etag string, lastModified time.Time) {
+	markPrivate(ctx)
+	if NotModified(ctx, etag, lastModified) {
+		return
+	}
+	Success(ctx, "read", data)
+}
+
+// ConditionalSuccessWithMeta is SuccessWithMeta for listings; the weak ETag covers both data and meta. Like
+// ConditionalSuccess, it marks the response private.
+func ConditionalSuccessWithMeta(ctx *gin.Context, data interface{}, meta interface{}) {
+	markPrivate(ctx)
+	etag, err := WeakETag(Response{Data: data, Meta: meta})
+	if err != nil {
+		InternalServerError(ctx, err)
+		return
+	}
+	if NotModified(ctx, etag, time.Time{}) {
+		return
+	}
+	SuccessWithMeta(ctx, data, meta)
+}
@@ -0,0 +1,128 @@
+package responsejson_test
+
+import (
+	"go-gin-project/helper/responsejson"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var lastModified = time.Date(2024, 5, 1, 10, 30, 15, 500, time.UTC)
+
+func setupRouter(etag string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", func(ctx *gin.Context) {
+		if responsejson.NotModified(ctx, etag, lastModified) {
+			return
+		}
+		responsejson.Success(ctx, "read", "payload")
+	})
+	return router
+}
+
+func serve(router *gin.Engine, header string, value string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/resource", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNotModified(t *testing.T) {
+	router := setupRouter(responsejson.StrongETag("7"))
+
+	t.Run("should set validators on a full response", func(t *testing.T) {
+		w := serve(router, "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+		assert.Equal(t, "Wed, 01 May 2024 10:30:15 GMT", w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("should answer 304 without body for matching If-None-Match", func(t *testing.T) {
+		w := serve(router, "If-None-Match", `"3", W/"7"`)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, `"7"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("should return full response for a different entity tag", func(t *testing.T) {
+		w := serve(router, "If-None-Match", `"6"`)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should match wildcard", func(t *testing.T) {
+		w := serve(router, "If-None-Match", "*")
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("should answer 304 when not modified since", func(t *testing.T) {
+		w := serve(router, "If-Modified-Since", "Wed, 01 May 2024 10:30:15 GMT")
+		assert.Equal(t, http.StatusNotModified, w.Code)
+	})
+
+	t.Run("should return full response when modified since", func(t *testing.T) {
+		w := serve(router, "If-Modified-Since", "Wed, 01 May 2024 10:30:14 GMT")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should ignore If-Modified-Since when If-None-Match is present", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/resource", nil)
+		req.Header.Set("If-None-Match", `"6"`)
+		req.Header.Set("If-Modified-Since", "Wed, 01 May 2024 10:30:15 GMT")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestWeakETag(t *testing.T) {
+	first, err := responsejson.WeakETag([]string{"a", "b"})
+	assert.Nil(t, err)
+	second, _ := responsejson.WeakETag([]string{"a", "b"})
+	third, _ := responsejson.WeakETag([]string{"a", "c"})
+
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, first)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, third)
+}
+
+func TestConditionalSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/resource", func(ctx *gin.Context) {
+		responsejson.ConditionalSuccess(ctx, "payload", responsejson.StrongETag("1-7"), lastModified)
+	})
+	router.GET("/resources", func(ctx *gin.Context) {
+		responsejson.ConditionalSuccessWithMeta(ctx, []string{"payload"}, nil)
+	})
+
+	t.Run("should keep responses out of shared caches", func(t *testing.T) {
+		for _, path := range []string{"/resource", "/resources"} {
+			req, _ := http.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code, path)
+			assert.Equal(t, "private", w.Header().Get("Cache-Control"), path)
+			assert.Equal(t, "Authorization, X-API-Key, X-Tenant-ID", w.Header().Get("Vary"), path)
+		}
+	})
+
+	t.Run("should mark a 304 private too", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/resource", nil)
+		req.Header.Set("If-None-Match", `"1-7"`)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, "private", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "Authorization, X-API-Key, X-Tenant-ID", w.Header().Get("Vary"))
+	})
+}
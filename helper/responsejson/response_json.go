@@ -10,6 +10,7 @@ type Response struct {
 	Code   int         `json:"code"`
 	Status string      `json:"status"`
 	Data   interface{} `json:"data"`
+	Meta   interface{} `json:"meta,omitempty"`
 }
 
 // Create, Read, Update, Delete
@@ -42,8 +43,33 @@ func Success(ctx *gin.Context, action string, data interface{}) {
 	})
 }
 
+// Read with pagination or other listing metadata placed next to data
+func SuccessWithMeta(ctx *gin.Context, data interface{}, meta interface{}) {
+	ctx.JSON(http.StatusOK, Response{
+		Code:   http.StatusOK,
+		Status: "Successfully retrieved data",
+		Data:   data,
+		Meta:   meta,
+	})
+}
+
+// Bulk reports the per-item results of a batch operation; code describes the batch as a whole
+func Bulk(ctx *gin.Context, code int, results interface{}, meta interface{}) {
+	status := http.StatusText(code)
+	if code < http.StatusMultipleChoices && code != http.StatusMultiStatus {
+		status = "Successfully processed"
+	}
+	ctx.JSON(code, Response{
+		Code:   code,
+		Status: status,
+		Data:   results,
+		Meta:   meta,
+	})
+}
 
+// InternalServerError also attaches err to the context, so the access log records it.
 func InternalServerError(ctx *gin.Context, err error) {
+	_ = ctx.Error(err)
 	ctx.JSON(http.StatusInternalServerError, Response{
 		Code:   http.StatusInternalServerError,
 		Status: "Internal Server Error",
@@ -75,33 +101,50 @@ func Unauthorized(ctx *gin.Context) {
 	})
 }
 
-// func Forbidden(ctx *gin.Context, message string) {
-// 	ctx.JSON(http.StatusForbidden, Response{
-// 		Code:   http.StatusForbidden,
-// 		Status: "Forbidden",
-// 		Data:   message,
-// 	})
-// }
-// func Conflict(ctx *gin.Context, message string) {
-// 	ctx.JSON(http.StatusConflict, Response{
-// 		Code:   http.StatusConflict,
-// 		Status: "Conflict",
-// 		Data:   message,
-// 	})
-// }
-
-// func UnprocessableEntity(ctx *gin.Context, err error) {
-// 	ctx.JSON(http.StatusUnprocessableEntity, Response{
-// 		Code:   http.StatusUnprocessableEntity,
-// 		Status: "Unprocessable Entity",
-// 		Data:   err.Error(),
-// 	})
-// }
-
-// func TooManyRequests(ctx *gin.Context, message string) {
-// 	ctx.JSON(http.StatusTooManyRequests, Response{
-// 		Code:   http.StatusTooManyRequests,
-// 		Status: "Too Many Requests",
-// 		Data:   message,
-// 	})
-// }
+func Conflict(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusConflict, Response{
+		Code:   http.StatusConflict,
+		Status: "Conflict",
+		Data:   message,
+	})
+}
+
+func PreconditionFailed(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusPreconditionFailed, Response{
+		Code:   http.StatusPreconditionFailed,
+		Status: "Precondition Failed",
+		Data:   message,
+	})
+}
+
+func UnprocessableEntity(ctx *gin.Context, err error) {
+	ctx.JSON(http.StatusUnprocessableEntity, Response{
+		Code:   http.StatusUnprocessableEntity,
+		Status: "Unprocessable Entity",
+		Data:   err.Error(),
+	})
+}
+
+func UnsupportedMediaType(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusUnsupportedMediaType, Response{
+		Code:   http.StatusUnsupportedMediaType,
+		Status: "Unsupported Media Type",
+		Data:   message,
+	})
+}
+
+func Forbidden(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusForbidden, Response{
+		Code:   http.StatusForbidden,
+		Status: "Forbidden",
+		Data:   message,
+	})
+}
+
+func TooManyRequests(ctx *gin.Context, message string) {
+	ctx.JSON(http.StatusTooManyRequests, Response{
+		Code:   http.StatusTooManyRequests,
+		Status: "Too Many Requests",
+		Data:   message,
+	})
+}
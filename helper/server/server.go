@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/helper/health"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ErrShuttingDown fails the readiness check a Server registers once it starts shutting down.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Server runs an http.Server until its context ends and then shuts it down in order: readiness fails first, the
+// server keeps serving for DrainDelay so load balancers can take it out of rotation, in-flight requests get up to
+// ShutdownTimeout to finish, and finally the OnShutdown functions get another ShutdownTimeout to release what the
+// server used.
+type Server struct {
+	HTTP            *http.Server
+	Health          *health.Registry
+	DrainDelay      time.Duration
+	ShutdownTimeout time.Duration
+
+	closers []func(ctx context.Context) error
+}
+
+func New(httpServer *http.Server, registry *health.Registry, drainDelay time.Duration, shutdownTimeout time.Duration) *Server {
+	return &Server{
+		HTTP:            httpServer,
+		Health:          registry,
+		DrainDelay:      drainDelay,
+		ShutdownTimeout: shutdownTimeout,
+	}
+}
+
+// OnShutdown adds a function to run once requests have drained, such as closing the database pool. Functions run
+// in the order they were added and share a ShutdownTimeout of their own, which starts once requests are done, so
+// a slow drain cannot leave them with an expired context.
+func (s *Server) OnShutdown(close func(ctx context.Context) error) {
+	s.closers = append(s.closers, close)
+}
+
+// ListenAndServe listens on the address of the http.Server and serves until ctx ends; see Serve.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.HTTP.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx ends and then shuts down gracefully. It returns nil when every request
+// finished and every OnShutdown function succeeded within the deadline.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- s.HTTP.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return errors.Join(err, s.close())
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "drain_delay", s.DrainDelay, "timeout", s.ShutdownTimeout)
+	s.Health.Register("shutdown", func(context.Context) error {
+		return ErrShuttingDown
+	})
+	time.Sleep(s.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+	err := s.HTTP.Shutdown(shutdownCtx)
+	if err != nil {
+		slog.Warn("requests still in flight at the shutdown deadline", "error", err)
+		_ = s.HTTP.Close()
+	}
+	if served := <-served; !errors.Is(served, http.ErrServerClosed) {
+		err = errors.Join(err, served)
+	}
+	return errors.Join(err, s.close())
+}
+
+// close runs every OnShutdown function, even after one of them failed.
+func (s *Server) close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, close := range s.closers {
+		errs = append(errs, close(ctx))
+	}
+	return errors.Join(errs...)
+}
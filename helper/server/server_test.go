@@ -0,0 +1,131 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/helper/health"
+	"go-gin-project/helper/server"
+	"io"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testServer struct {
+	url      string
+	registry *health.Registry
+	held     chan struct{}
+	release  chan struct{}
+	stopped  chan error
+	closed   []string
+	// closeErrs holds ctx.Err() as each OnShutdown function saw it.
+	closeErrs []error
+}
+
+// startServer serves on a random local port until ctx ends. Requests to /slow are held until release is closed.
+func startServer(t *testing.T, ctx context.Context, drainDelay, timeout time.Duration) *testServer {
+	s := &testServer{
+		registry: health.NewRegistry(time.Second),
+		held:     make(chan struct{}, 1),
+		release:  make(chan struct{}),
+		stopped:  make(chan error, 1),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		s.held <- struct{}{}
+		<-s.release
+		_, _ = io.WriteString(w, "done")
+	})
+
+	srv := server.New(&http.Server{Handler: mux}, s.registry, drainDelay, timeout)
+	srv.OnShutdown(func(ctx context.Context) error {
+		s.closed = append(s.closed, "database")
+		s.closeErrs = append(s.closeErrs, ctx.Err())
+		return nil
+	})
+	srv.OnShutdown(func(ctx context.Context) error {
+		s.closed = append(s.closed, "tracing")
+		s.closeErrs = append(s.closeErrs, ctx.Err())
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	s.url = "http://" + listener.Addr().String()
+	go func() {
+		s.stopped <- srv.Serve(ctx, listener)
+	}()
+	return s
+}
+
+// slowRequest sends a request to /slow, returns once the server holds it and delivers the body when it completes.
+func (s *testServer) slowRequest() chan string {
+	body := make(chan string, 1)
+	go func() {
+		res, err := http.Get(s.url + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		read, _ := io.ReadAll(res.Body)
+		body <- string(read)
+	}()
+	<-s.held
+	return body
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should fail readiness, drain in-flight requests and close resources", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := startServer(t, ctx, 100*time.Millisecond, time.Second)
+		assert.True(t, s.registry.Run(context.Background()).Healthy())
+		body := s.slowRequest()
+
+		cancel()
+		assert.Eventually(t, func() bool {
+			return !s.registry.Run(context.Background()).Healthy()
+		}, time.Second, 5*time.Millisecond)
+		report := s.registry.Run(context.Background())
+		assert.Equal(t, server.ErrShuttingDown.Error(), report.Checks["shutdown"].Error)
+
+		close(s.release)
+		assert.Equal(t, "done", <-body)
+		assert.Nil(t, <-s.stopped)
+		assert.Equal(t, []string{"database", "tracing"}, s.closed)
+
+		_, err := http.Get(s.url + "/slow")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("should give up on requests still running at the deadline", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := startServer(t, ctx, 0, 50*time.Millisecond)
+		defer close(s.release)
+		s.slowRequest()
+
+		cancel()
+		err := <-s.stopped
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+		assert.Equal(t, []string{"database", "tracing"}, s.closed)
+		assert.Equal(t, []error{nil, nil}, s.closeErrs)
+	})
+
+	t.Run("should shut down on SIGTERM", func(t *testing.T) {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+		defer stop()
+		s := startServer(t, ctx, 0, time.Second)
+		body := s.slowRequest()
+
+		assert.Nil(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+		<-ctx.Done()
+		close(s.release)
+		assert.Equal(t, "done", <-body)
+		assert.Nil(t, <-s.stopped)
+	})
+}
@@ -0,0 +1,31 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+// Header is the request header a client names its tenant in when its credential carries none and may pick one.
+const Header = "X-Tenant-ID"
+
+// Default owns the rows created before tenants existed.
+const Default = "default"
+
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+type contextKey struct{}
+
+// Valid reports whether id can be used as a tenant id.
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant the request in ctx acts for.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
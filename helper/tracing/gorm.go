@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// gormSpan is kept on the statement between the callbacks, with the context to restore once the span ends.
+type gormSpan struct {
+	span   trace.Span
+	parent context.Context
+}
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`([^\w$.]|^)-?\d+(?:\.\d+)?\b`)
+)
+
+// SanitizeSQL replaces the string and numeric literals of a statement with ?, so values written into raw SQL do not
+// end up in traces. Placeholders such as $1 are kept.
+func SanitizeSQL(sql string) string {
+	sql = stringLiteral.ReplaceAllString(sql, "?")
+	return numericLiteral.ReplaceAllString(sql, "${1}?")
+}
+
+// GormPlugin starts a client span around every statement GORM runs, as a child of the span in the statement's
+// context. Spans carry the sanitized SQL but never the query parameters.
+type GormPlugin struct {
+	tracer trace.Tracer
+}
+
+func NewGormPlugin(provider trace.TracerProvider) *GormPlugin {
+	return &GormPlugin{tracer: provider.Tracer(InstrumentationName)}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize wraps the statement of every callback chain. A query span ends before its preloads run, so they become
+// its siblings rather than its children.
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tracing:create:before", p.start("INSERT")),
+		callbacks.Create().After("gorm:create").Register("tracing:create:after", p.end),
+		callbacks.Query().Before("gorm:query").Register("tracing:query:before", p.start("SELECT")),
+		callbacks.Query().After("gorm:query").Before("gorm:preload").Register("tracing:query:after", p.end),
+		callbacks.Update().Before("gorm:update").Register("tracing:update:before", p.start("UPDATE")),
+		callbacks.Update().After("gorm:update").Register("tracing:update:after", p.end),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:delete:before", p.start("DELETE")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:delete:after", p.end),
+		callbacks.Row().Before("gorm:row").Register("tracing:row:before", p.start("query")),
+		callbacks.Row().After("gorm:row").Register("tracing:row:after", p.end),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:raw:before", p.start("query")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:raw:after", p.end),
+	)
+}
+
+// start names the span after the operation and table, as in "SELECT tags"; the SQL is not built yet at this point.
+func (p *GormPlugin) start(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		name := operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		parent := db.Statement.Context
+		ctx, span := p.tracer.Start(parent, name, trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, gormSpan{span: span, parent: parent})
+	}
+}
+
+func (p *GormPlugin) end(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	started := value.(gormSpan)
+	span := started.span
+	db.Statement.Context = started.parent
+
+	sql := db.Statement.SQL.String()
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemKey.String(db.Dialector.Name()),
+		semconv.DBQueryText(SanitizeSQL(sql)),
+		attribute.Int64("db.response.rows", db.RowsAffected),
+	}
+	if operation, _, _ := strings.Cut(strings.TrimSpace(sql), " "); operation != "" {
+		attrs = append(attrs, semconv.DBOperationName(strings.ToUpper(operation)))
+	}
+	if db.Statement.Table != "" {
+		attrs = append(attrs, semconv.DBCollectionName(db.Statement.Table))
+	}
+	span.SetAttributes(attrs...)
+
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName names the tracer every span of the service is started with.
+const InstrumentationName = "go-gin-project"
+
+// The exporters NewProvider accepts.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOtlp   = "otlp"
+)
+
+// ErrUnknownExporter is returned by NewProvider for an exporter it does not know.
+var ErrUnknownExporter = errors.New("unknown trace exporter")
+
+// Options choose where a provider from NewProvider sends its spans.
+type Options struct {
+	// Exporter is "otlp", "stdout" or "none"; "" means none.
+	Exporter string
+	// ServiceName names the service in every span unless OTEL_SERVICE_NAME is set.
+	ServiceName string
+	// OtlpEndpoint is the base URL of the OTLP/HTTP collector, such as http://collector:4318; spans are sent to its
+	// /v1/traces path. When empty the exporter reads the standard OTEL_EXPORTER_OTLP_* variables.
+	OtlpEndpoint string
+	// Out receives the spans of the stdout exporter.
+	Out io.Writer
+}
+
+// NewProvider returns a provider that batches spans to the exporter of options: "otlp" sends them over OTLP/HTTP,
+// "stdout" writes them to Out as JSON and "none" records nothing.
+func NewProvider(ctx context.Context, options Options) (trace.TracerProvider, error) {
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch options.Exporter {
+	case ExporterNone, "":
+		return noop.NewTracerProvider(), nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(options.Out))
+	case ExporterOtlp:
+		var otlpOptions []otlptracehttp.Option
+		if options.OtlpEndpoint != "" {
+			otlpOptions = append(otlpOptions, otlptracehttp.WithEndpointURL(strings.TrimSuffix(options.OtlpEndpoint, "/")+"/v1/traces"))
+		}
+		spanExporter, err = otlptracehttp.New(ctx, otlpOptions...)
+	default:
+		return nil, fmt.Errorf("%w %q: use otlp, stdout or none", ErrUnknownExporter, options.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(options.ServiceName)),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(res)), nil
+}
+
+// Shutdown flushes the spans a provider from NewProvider still holds; providers that export nothing are left alone.
+func Shutdown(ctx context.Context, provider trace.TracerProvider) error {
+	if sdkProvider, ok := provider.(*sdktrace.TracerProvider); ok {
+		return sdkProvider.Shutdown(ctx)
+	}
+	return nil
+}
+
+// End finishes a span, marking it failed when err is set and recording the error on it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,119 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-gin-project/helper/tracing"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type note struct {
+	Id   int
+	Body string
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	sqls := map[string]string{
+		`SELECT * FROM "tags" WHERE name = 'it''s secret' AND id = 42 LIMIT 1`: `SELECT * FROM "tags" WHERE name = ? AND id = ? LIMIT ?`,
+		`SELECT * FROM tags_2 WHERE id = $1 AND version = -3.5`:                `SELECT * FROM tags_2 WHERE id = $1 AND version = ?`,
+		`UPDATE "tags" SET "name"=? WHERE "id" = ?`:                            `UPDATE "tags" SET "name"=? WHERE "id" = ?`,
+	}
+	for sql, sanitized := range sqls {
+		assert.Equal(t, sanitized, tracing.SanitizeSQL(sql))
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Run("should record nothing without an exporter", func(t *testing.T) {
+		provider, err := tracing.NewProvider(context.Background(), tracing.Options{Exporter: tracing.ExporterNone})
+		assert.Nil(t, err)
+		_, span := provider.Tracer("test").Start(context.Background(), "span")
+		assert.False(t, span.IsRecording())
+		assert.Nil(t, tracing.Shutdown(context.Background(), provider))
+	})
+
+	t.Run("should write spans to stdout on shutdown", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		provider, err := tracing.NewProvider(context.Background(), tracing.Options{Exporter: tracing.ExporterStdout, ServiceName: "test", Out: out})
+		assert.Nil(t, err)
+		_, span := provider.Tracer("test").Start(context.Background(), "exported span")
+		span.End()
+
+		assert.Nil(t, tracing.Shutdown(context.Background(), provider))
+		assert.Contains(t, out.String(), `"Name":"exported span"`)
+	})
+
+	t.Run("should reject an unknown exporter", func(t *testing.T) {
+		_, err := tracing.NewProvider(context.Background(), tracing.Options{Exporter: "zipkin"})
+		assert.ErrorIs(t, err, tracing.ErrUnknownExporter)
+	})
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&note{}))
+	assert.Nil(t, db.Use(tracing.NewGormPlugin(provider)))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	db.WithContext(ctx).Create(&note{Body: "secret body"})
+	var found note
+	err = db.WithContext(ctx).Where("body = ?", "secret body").First(&found).Error
+	assert.Nil(t, err)
+	err = db.WithContext(ctx).First(&found, 99).Error
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	db.WithContext(ctx).Exec("UPDATE notes SET body = 'raw secret' WHERE id = 1")
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 5)
+	names := []string{}
+	for _, span := range spans[:4] {
+		names = append(names, span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, "sqlite", attributeValue(span, "db.system"))
+		assert.False(t, strings.Contains(attributeValue(span, "db.query.text"), "secret"))
+		assert.Equal(t, codes.Unset, span.Status().Code)
+	}
+	assert.Equal(t, []string{"INSERT notes", "SELECT notes", "SELECT notes", "query"}, names)
+	assert.Equal(t, "SELECT", attributeValue(spans[1], "db.operation.name"))
+	assert.Equal(t, "notes", attributeValue(spans[1], "db.collection.name"))
+	assert.Equal(t, "UPDATE notes SET body = ? WHERE id = ?", attributeValue(spans[3], "db.query.text"))
+}
+
+func TestEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "failed")
+	tracing.End(span, errors.New("boom"))
+	_, span = tracer.Start(context.Background(), "succeeded")
+	tracing.End(span, nil)
+
+	spans := recorder.Ended()
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "boom", spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+}
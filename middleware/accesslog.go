@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"go-gin-project/helper/logging"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLog logs every request once it is served, through the request's logger so the entry carries the request
+// id. Server errors are logged as errors and client errors as warnings, together with the errors handlers attached
+// to the gin.Context.
+func AccessLog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		request := ctx.Request
+		attrs := []slog.Attr{
+			slog.String("method", request.Method),
+			slog.String("route", ctx.FullPath()),
+			slog.String("path", request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if subject := ctx.GetString(SubjectKey); subject != "" {
+			attrs = append(attrs, slog.String("subject", subject))
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+		logging.FromContext(request.Context()).LogAttrs(request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recover answers a panicking handler with 500 Internal Server Error and logs the panic and its stack through the
+// request's logger instead of gin's plain text output.
+func Recover() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		request := ctx.Request
+		logging.FromContext(request.Context()).ErrorContext(request.Context(), "panic serving request",
+			slog.Any("panic", recovered), slog.String("stack", string(debug.Stack())))
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
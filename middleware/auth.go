@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/data"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/responsejson"
+	"go-gin-project/helper/tenant"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Keys under which Authenticate stores the caller in gin.Context. ClaimsKey is set for bearer tokens and ApiKeyKey
+// for API keys; ScopesKey holds the scopes of either.
+const (
+	SubjectKey = "auth.subject"
+	ClaimsKey  = "auth.claims"
+	ApiKeyKey  = "auth.api_key"
+	ScopesKey  = "auth.scopes"
+)
+
+// Token claims Authenticate reads besides the registered ones. ScopeClaim is a space separated list of scopes and
+// RolesClaim a list of roles, which grant the scopes declared for them in package auth.
+const (
+	TenantClaim = "tenant"
+	ScopeClaim  = "scope"
+	RolesClaim  = "roles"
+)
+
+// ApiKeyHeader carries an API key as an alternative to "Authorization: ApiKey <key>".
+const ApiKeyHeader = "X-API-Key"
+
+// ApiKeyAuthenticator resolves a plaintext API key, failing with auth.ErrInvalidApiKey when it is not usable.
+type ApiKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (data.ApiKeyResponse, error)
+}
+
+// Authenticate requires a valid bearer token or API key on every route except the exempt route paths, such as
+// "/ping". For tokens the subject and claims are stored under SubjectKey and ClaimsKey; for API keys the subject is
+// "api-key:<prefix>" and the key and its scopes are stored under ApiKeyKey and ScopesKey. A tenant named by the
+// credential is placed in the request context for Tenant. apiKeys may be nil to accept bearer tokens only.
+func Authenticate(verifier *auth.Verifier, apiKeys ApiKeyAuthenticator, exempt ...string) gin.HandlerFunc {
+	open := map[string]bool{}
+	for _, path := range exempt {
+		open[path] = true
+	}
+
+	return func(ctx *gin.Context) {
+		if open[ctx.FullPath()] {
+			ctx.Next()
+			return
+		}
+
+		if key := ctx.GetHeader(ApiKeyHeader); key != "" {
+			authenticateApiKey(ctx, apiKeys, key)
+			return
+		}
+		scheme, credential, found := strings.Cut(ctx.GetHeader("Authorization"), " ")
+		if !found || credential == "" {
+			unauthorized(ctx)
+			return
+		}
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			authenticateToken(ctx, verifier, credential)
+		case strings.EqualFold(scheme, "ApiKey"):
+			authenticateApiKey(ctx, apiKeys, credential)
+		default:
+			unauthorized(ctx)
+		}
+	}
+}
+
+func authenticateToken(ctx *gin.Context, verifier *auth.Verifier, token string) {
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		unauthorized(ctx)
+		return
+	}
+
+	subject, _ := claims.GetSubject()
+	ctx.Set(SubjectKey, subject)
+	ctx.Set(ClaimsKey, claims)
+	ctx.Set(ScopesKey, tokenScopes(claims))
+	if tenantId, ok := claims[TenantClaim]; ok {
+		tenantId, _ := tenantId.(string)
+		if !tenant.Valid(tenantId) {
+			unauthorized(ctx)
+			return
+		}
+		ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), tenantId))
+	}
+	ctx.Next()
+}
+
+// tokenScopes collects the scopes of the scope claim and of the roles in the roles claim. Malformed claims grant nothing.
+func tokenScopes(claims jwt.MapClaims) []string {
+	scope, _ := claims[ScopeClaim].(string)
+	scopes := strings.Fields(scope)
+
+	roles, _ := claims[RolesClaim].([]any)
+	names := []string{}
+	for _, role := range roles {
+		if name, ok := role.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return append(scopes, auth.RoleScopes(names)...)
+}
+
+func authenticateApiKey(ctx *gin.Context, apiKeys ApiKeyAuthenticator, plaintext string) {
+	if apiKeys == nil {
+		unauthorized(ctx)
+		return
+	}
+	key, err := apiKeys.Authenticate(ctx.Request.Context(), plaintext)
+	if errors.Is(err, auth.ErrInvalidApiKey) {
+		unauthorized(ctx)
+		return
+	} else if err != nil {
+		responsejson.InternalServerError(ctx, err)
+		ctx.Abort()
+		return
+	}
+
+	ctx.Set(SubjectKey, "api-key:"+key.Prefix)
+	ctx.Set(ApiKeyKey, key)
+	ctx.Set(ScopesKey, key.Scopes)
+	if key.TenantId != "" {
+		ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), key.TenantId))
+	}
+	ctx.Next()
+}
+
+func unauthorized(ctx *gin.Context) {
+	ctx.Header("WWW-Authenticate", `Bearer realm="api", ApiKey realm="api"`)
+	responsejson.Unauthorized(ctx)
+	ctx.Abort()
+}
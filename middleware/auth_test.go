@@ -0,0 +1,214 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/data"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/tenant"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var authSecret = []byte("test-secret")
+
+// fakeApiKeys accepts the keys it holds and fails on "tk_broken0_secret" as a storage error would.
+type fakeApiKeys map[string]data.ApiKeyResponse
+
+func (f fakeApiKeys) Authenticate(_ context.Context, key string) (data.ApiKeyResponse, error) {
+	if key == "tk_broken0_secret" {
+		return data.ApiKeyResponse{}, errors.New("database is down")
+	}
+	found, ok := f[key]
+	if !ok {
+		return data.ApiKeyResponse{}, auth.ErrInvalidApiKey
+	}
+	return found, nil
+}
+
+var testApiKeys = fakeApiKeys{
+	"tk_0123abcd_secret": {Prefix: "tk_0123abcd", Scopes: []string{"tags:read"}},
+	"tk_team0000_secret": {Prefix: "tk_team0000", TenantId: "team-c"},
+}
+
+func setupAuthRouter(t *testing.T) *gin.Engine {
+	verifier, err := auth.NewVerifier(auth.Options{Secret: authSecret})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Authenticate(verifier, testApiKeys, "/ping"))
+	router.GET("/ping", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "pong")
+	})
+	router.GET("/me", func(ctx *gin.Context) {
+		if claims, ok := ctx.Get(middleware.ClaimsKey); ok {
+			ctx.JSON(http.StatusOK, gin.H{"subject": ctx.GetString(middleware.SubjectKey), "role": claims.(jwt.MapClaims)["role"]})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"subject": ctx.GetString(middleware.SubjectKey), "scopes": ctx.GetStringSlice(middleware.ScopesKey)})
+	})
+	router.GET("/tag", middleware.Tenant(), func(ctx *gin.Context) {
+		tenantId, _ := tenant.FromContext(ctx.Request.Context())
+		ctx.String(http.StatusOK, tenantId)
+	})
+	return router
+}
+
+func signToken(claims jwt.MapClaims, key []byte) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	return token
+}
+
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "user-1", "role": "editor", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func serveAuth(router *gin.Engine, path, authorization string, headers ...string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	req.Header.Set(tenant.Header, "team-a")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthenticate(t *testing.T) {
+	router := setupAuthRouter(t)
+
+	t.Run("should store the subject and claims of a valid token", func(t *testing.T) {
+		w := serveAuth(router, "/me", "Bearer "+signToken(userClaims(), authSecret))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"subject":"user-1","role":"editor"}`, w.Body.String())
+	})
+
+	t.Run("should leave exempt routes open", func(t *testing.T) {
+		w := serveAuth(router, "/ping", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should reject missing and invalid tokens", func(t *testing.T) {
+		expired := userClaims()
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+		headers := map[string]string{
+			"missing":         "",
+			"wrong scheme":    "Basic dXNlcjpwYXNz",
+			"empty bearer":    "Bearer ",
+			"malformed":       "Bearer not-a-token",
+			"expired":         "Bearer " + signToken(expired, authSecret),
+			"wrong signature": "Bearer " + signToken(userClaims(), []byte("other-secret")),
+		}
+		for name, header := range headers {
+			w := serveAuth(router, "/me", header)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code, name)
+			assert.Equal(t, `Bearer realm="api", ApiKey realm="api"`, w.Header().Get("WWW-Authenticate"), name)
+		}
+	})
+
+	t.Run("should take the tenant from the token over the header", func(t *testing.T) {
+		claims := userClaims()
+		claims[middleware.TenantClaim] = "team-b"
+
+		w := serveAuth(router, "/tag", "Bearer "+signToken(claims, authSecret))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "team-b", w.Body.String())
+	})
+
+	t.Run("should not let a credential without a tenant pick one", func(t *testing.T) {
+		w := serveAuth(router, "/tag", "Bearer "+signToken(userClaims(), authSecret))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "tenants:any")
+
+		w = serveAuth(router, "/tag", "ApiKey tk_0123abcd_secret")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should let a cross-tenant credential pick a tenant with the header", func(t *testing.T) {
+		claims := userClaims()
+		claims[middleware.ScopeClaim] = "tenants:any"
+
+		w := serveAuth(router, "/tag", "Bearer "+signToken(claims, authSecret))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "team-a", w.Body.String())
+	})
+
+	t.Run("should keep the tenant of a cross-tenant credential that names one", func(t *testing.T) {
+		claims := userClaims()
+		claims[middleware.ScopeClaim] = "tenants:any"
+		claims[middleware.TenantClaim] = "team-b"
+
+		w := serveAuth(router, "/tag", "Bearer "+signToken(claims, authSecret))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "team-b", w.Body.String())
+	})
+
+	t.Run("should reject an invalid tenant claim", func(t *testing.T) {
+		claims := userClaims()
+		claims[middleware.TenantClaim] = "team b"
+
+		w := serveAuth(router, "/tag", "Bearer "+signToken(claims, authSecret))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should accept an API key in either header", func(t *testing.T) {
+		for _, w := range []*httptest.ResponseRecorder{
+			serveAuth(router, "/me", "ApiKey tk_0123abcd_secret"),
+			serveAuth(router, "/me", "", middleware.ApiKeyHeader, "tk_0123abcd_secret"),
+		} {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"subject":"api-key:tk_0123abcd","scopes":["tags:read"]}`, w.Body.String())
+		}
+	})
+
+	t.Run("should reject an unknown API key", func(t *testing.T) {
+		w := serveAuth(router, "/me", "", middleware.ApiKeyHeader, "tk_0123abcd_wrong")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = serveAuth(router, "/me", "ApiKey tk_0123abcd_wrong")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("should fail when keys cannot be checked", func(t *testing.T) {
+		w := serveAuth(router, "/me", "ApiKey tk_broken0_secret")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("should take the tenant from the API key", func(t *testing.T) {
+		w := serveAuth(router, "/tag", "ApiKey tk_team0000_secret")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "team-c", w.Body.String())
+	})
+
+	t.Run("should reject API keys without an authenticator", func(t *testing.T) {
+		verifier, _ := auth.NewVerifier(auth.Options{Secret: authSecret})
+		tokensOnly := gin.New()
+		tokensOnly.Use(middleware.Authenticate(verifier, nil))
+		tokensOnly.GET("/me", func(ctx *gin.Context) {})
+
+		w := serveAuth(tokensOnly, "/me", "ApiKey tk_0123abcd_secret")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
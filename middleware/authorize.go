@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/responsejson"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope lets a request through when the caller authenticated by Authenticate holds scope, directly or
+// through a broader scope, and answers 403 Forbidden otherwise. Scopes are declared in package auth.
+func RequireScope(scope string) gin.HandlerFunc {
+	if !auth.KnownScope(scope) {
+		panic("middleware: unknown scope " + scope)
+	}
+	return func(ctx *gin.Context) {
+		if !auth.Grants(ctx.GetStringSlice(ScopesKey), scope) {
+			responsejson.Forbidden(ctx, "requires scope "+scope)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"go-gin-project/helper/auth"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAuthorizeRouter(t *testing.T) *gin.Engine {
+	verifier, err := auth.NewVerifier(auth.Options{Secret: authSecret})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Authenticate(verifier, testApiKeys))
+	ok := func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	}
+	router.GET("/tag", middleware.RequireScope(auth.ScopeTagsRead), ok)
+	router.POST("/tag", middleware.RequireScope(auth.ScopeTagsWrite), ok)
+	router.DELETE("/tag", middleware.RequireScope(auth.ScopeTagsAdmin), ok)
+	return router
+}
+
+func serveAs(router *gin.Engine, method string, claims jwt.MapClaims) *httptest.ResponseRecorder {
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	req, _ := http.NewRequest(method, "/tag", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(claims, authSecret))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireScope(t *testing.T) {
+	router := setupAuthorizeRouter(t)
+
+	t.Run("should enforce the scope claim", func(t *testing.T) {
+		reader := jwt.MapClaims{"sub": "user-1", "scope": "tags:read other:scope"}
+
+		assert.Equal(t, http.StatusNoContent, serveAs(router, "GET", reader).Code)
+		w := serveAs(router, "POST", reader)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "requires scope tags:write")
+	})
+
+	t.Run("should grant the scopes of roles", func(t *testing.T) {
+		editor := func() jwt.MapClaims { return jwt.MapClaims{"sub": "user-1", "roles": []string{"editor"}} }
+		admin := func() jwt.MapClaims { return jwt.MapClaims{"sub": "user-1", "roles": []string{"admin"}} }
+
+		assert.Equal(t, http.StatusNoContent, serveAs(router, "GET", editor()).Code)
+		assert.Equal(t, http.StatusNoContent, serveAs(router, "POST", editor()).Code)
+		assert.Equal(t, http.StatusForbidden, serveAs(router, "DELETE", editor()).Code)
+		assert.Equal(t, http.StatusNoContent, serveAs(router, "DELETE", admin()).Code)
+	})
+
+	t.Run("should forbid a token without scopes", func(t *testing.T) {
+		w := serveAs(router, "GET", jwt.MapClaims{"sub": "user-1", "roles": "admin"})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should enforce the scopes of API keys", func(t *testing.T) {
+		for method, code := range map[string]int{"GET": http.StatusNoContent, "POST": http.StatusForbidden} {
+			req, _ := http.NewRequest(method, "/tag", nil)
+			req.Header.Set(middleware.ApiKeyHeader, "tk_0123abcd_secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, code, w.Code, method)
+		}
+	})
+
+	t.Run("should refuse undeclared scopes", func(t *testing.T) {
+		assert.Panics(t, func() { middleware.RequireScope("tags:delete") })
+	})
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"go-gin-project/helper/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests no route matched, so unknown paths cannot blow up the number of series.
+const unmatchedRoute = "unmatched"
+
+// Metrics counts and times every request by method, route template (such as /tag/:tagId) and status code,
+// and tracks the requests in flight.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		done := m.StartRequest(ctx.Request.Method, route)
+		ctx.Next()
+		done(ctx.Writer.Status())
+	}
+}
@@ -0,0 +1,35 @@
+package middleware_test
+
+import (
+	"go-gin-project/helper/metrics"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Metrics(m))
+	router.GET("/tag/:tagId", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	router.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/tag/1", "/tag/2", "/unknown/3"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="/tag/:tagId",status="204"} 2`)
+	assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.NotContains(t, w.Body.String(), `route="/tag/1"`)
+}
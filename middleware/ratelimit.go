@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"fmt"
+	"go-gin-project/data"
+	"go-gin-project/helper/ratelimit"
+	"go-gin-project/helper/responsejson"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPGroup names the buckets of RateLimitByIP.
+const IPGroup = "ip"
+
+// RateLimit limits each client of a route group to limit, with buckets named after the group so groups do not
+// share them. Clients are told apart by API key, then by authenticated subject, then by IP address, so RateLimit
+// belongs after Authenticate. Every response carries RateLimit-* headers and rejected requests a Retry-After.
+// When the store fails the request is let through rather than blocking all traffic.
+func RateLimit(store ratelimit.Store, group string, limit ratelimit.Limit) gin.HandlerFunc {
+	return rateLimit(store, group, limit, clientKey)
+}
+
+// RateLimitByIP limits every request by client IP address alone. It belongs before Authenticate, so requests
+// without or with wrong credentials are throttled too. The address is only taken from forwarding headers sent by
+// the engine's trusted proxies.
+func RateLimitByIP(store ratelimit.Store, limit ratelimit.Limit) gin.HandlerFunc {
+	return rateLimit(store, IPGroup, limit, func(ctx *gin.Context) string {
+		return "ip:" + ctx.ClientIP()
+	})
+}
+
+func rateLimit(store ratelimit.Store, group string, limit ratelimit.Limit, key func(ctx *gin.Context) string) gin.HandlerFunc {
+	if limit.Disabled() {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+	policy := fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, int(math.Ceil(limit.Per.Seconds())), limit.Burst)
+
+	return func(ctx *gin.Context) {
+		result, err := store.Take(ctx.Request.Context(), group+":"+key(ctx), limit)
+		if err != nil {
+			_ = ctx.Error(err)
+			ctx.Next()
+			return
+		}
+
+		ctx.Header("RateLimit-Policy", policy)
+		ctx.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Header("RateLimit-Reset", seconds(result.Reset))
+		if !result.Allowed {
+			ctx.Header("Retry-After", seconds(result.RetryAfter))
+			responsejson.TooManyRequests(ctx, "rate limit exceeded, retry in "+seconds(result.RetryAfter)+"s")
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// clientKey names the caller for rate limiting.
+func clientKey(ctx *gin.Context) string {
+	if key, ok := ctx.Get(ApiKeyKey); ok {
+		return "api-key:" + key.(data.ApiKeyResponse).Prefix
+	}
+	if subject := ctx.GetString(SubjectKey); subject != "" {
+		return "user:" + subject
+	}
+	return "ip:" + ctx.ClientIP()
+}
+
+// seconds rounds up, so clients that wait as told are not turned away again.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,161 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"go-gin-project/data"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/ratelimit"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var twoPerMinute = ratelimit.Limit{Requests: 2, Per: time.Minute, Burst: 2}
+
+func setupRateLimitRouter(store ratelimit.Store, limit ratelimit.Limit) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(ctx *gin.Context) {
+		if prefix := ctx.GetHeader("Test-Api-Key"); prefix != "" {
+			ctx.Set(middleware.SubjectKey, "api-key:"+prefix)
+			ctx.Set(middleware.ApiKeyKey, data.ApiKeyResponse{Prefix: prefix})
+		} else if subject := ctx.GetHeader("Test-Subject"); subject != "" {
+			ctx.Set(middleware.SubjectKey, subject)
+		}
+	})
+	ok := func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	}
+	router.POST("/tag", middleware.RateLimit(store, "tag", limit), ok)
+	router.POST("/todo", middleware.RateLimit(store, "todo", limit), ok)
+	return router
+}
+
+func serveLimited(router *gin.Engine, path string, headers ...string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("POST", path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is down")
+}
+
+func TestRateLimit(t *testing.T) {
+	t.Run("should report the budget and reject requests over it", func(t *testing.T) {
+		router := setupRateLimitRouter(ratelimit.NewMemoryStore(), twoPerMinute)
+
+		w := serveLimited(router, "/tag")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "2;w=60;burst=2", w.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+
+		serveLimited(router, "/tag")
+		w = serveLimited(router, "/tag")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "Too Many Requests")
+	})
+
+	t.Run("should keep route groups apart", func(t *testing.T) {
+		router := setupRateLimitRouter(ratelimit.NewMemoryStore(), twoPerMinute)
+		serveLimited(router, "/tag")
+		serveLimited(router, "/tag")
+
+		assert.Equal(t, http.StatusNoContent, serveLimited(router, "/todo").Code)
+	})
+
+	t.Run("should key by API key, then subject, then IP", func(t *testing.T) {
+		router := setupRateLimitRouter(ratelimit.NewMemoryStore(), twoPerMinute)
+		for range 2 {
+			serveLimited(router, "/tag")
+			serveLimited(router, "/tag", "Test-Subject", "user-1")
+			serveLimited(router, "/tag", "Test-Api-Key", "tk_0123abcd")
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, serveLimited(router, "/tag").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serveLimited(router, "/tag", "Test-Subject", "user-1").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serveLimited(router, "/tag", "Test-Api-Key", "tk_0123abcd").Code)
+		assert.Equal(t, http.StatusNoContent, serveLimited(router, "/tag", "Test-Subject", "user-2").Code)
+		assert.Equal(t, http.StatusNoContent, serveLimited(router, "/tag", "Test-Api-Key", "tk_4567ef01").Code)
+	})
+
+	t.Run("should let requests through when the store fails", func(t *testing.T) {
+		router := setupRateLimitRouter(failingStore{}, twoPerMinute)
+
+		w := serveLimited(router, "/tag")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("should do nothing when disabled", func(t *testing.T) {
+		router := setupRateLimitRouter(failingStore{}, ratelimit.Limit{})
+
+		for range 5 {
+			w := serveLimited(router, "/tag")
+			assert.Equal(t, http.StatusNoContent, w.Code)
+			assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+}
+
+func setupIPRateLimitRouter(t *testing.T, trustedProxies []string) *gin.Engine {
+	verifier, err := auth.NewVerifier(auth.Options{Secret: authSecret})
+	assert.Nil(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	assert.Nil(t, router.SetTrustedProxies(trustedProxies))
+	router.Use(middleware.RateLimitByIP(ratelimit.NewMemoryStore(), twoPerMinute))
+	router.Use(middleware.Authenticate(verifier, testApiKeys))
+	router.POST("/tag", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	t.Run("should throttle requests that fail to authenticate", func(t *testing.T) {
+		router := setupIPRateLimitRouter(t, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, serveLimited(router, "/tag", "Authorization", "Bearer guess-1").Code)
+		assert.Equal(t, http.StatusUnauthorized, serveLimited(router, "/tag", middleware.ApiKeyHeader, "tk_0123abcd_guess").Code)
+		w := serveLimited(router, "/tag", "Authorization", "Bearer guess-3")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("should ignore forwarding headers from untrusted peers", func(t *testing.T) {
+		router := setupIPRateLimitRouter(t, nil)
+		serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.1")
+		serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.2")
+
+		assert.Equal(t, http.StatusTooManyRequests, serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.3").Code)
+	})
+
+	t.Run("should tell clients apart behind a trusted proxy", func(t *testing.T) {
+		router := setupIPRateLimitRouter(t, []string{"192.0.2.0/24"})
+		serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.1")
+		serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.1")
+
+		assert.Equal(t, http.StatusTooManyRequests, serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.1").Code)
+		assert.Equal(t, http.StatusUnauthorized, serveLimited(router, "/tag", "X-Forwarded-For", "198.51.100.2").Code)
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go-gin-project/helper/logging"
+	"log/slog"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIdHeader carries the request id in both directions.
+const RequestIdHeader = "X-Request-ID"
+
+// RequestIdKey is the gin.Context key RequestId stores the id under.
+const RequestIdKey = "request.id"
+
+// requestIdPattern accepts incoming ids that are safe to echo back and to log.
+var requestIdPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestId names every request, keeping a well-formed X-Request-ID sent by the caller and generating one otherwise.
+// The id is echoed in the response and attached to the logger placed in the request context for all later layers.
+func RequestId(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIdHeader)
+		if !requestIdPattern.MatchString(id) {
+			id = newRequestId()
+		}
+
+		ctx.Set(RequestIdKey, id)
+		ctx.Header(RequestIdHeader, id)
+		requestLogger := logger.With(slog.String("request_id", id))
+		ctx.Request = ctx.Request.WithContext(logging.NewContext(ctx.Request.Context(), requestLogger))
+		ctx.Next()
+	}
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,128 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go-gin-project/helper/logging"
+	"go-gin-project/helper/responsejson"
+	"go-gin-project/middleware"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLoggingRouter() (*gin.Engine, *bytes.Buffer) {
+	var buffer bytes.Buffer
+	logger, _ := logging.New(&buffer, slog.LevelDebug, "json")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestId(logger), middleware.AccessLog(), middleware.Recover())
+	router.GET("/tag/:tagId", func(ctx *gin.Context) {
+		logging.FromContext(ctx.Request.Context()).Info("handler")
+		ctx.String(http.StatusOK, ctx.GetString(middleware.RequestIdKey))
+	})
+	router.GET("/fail", func(ctx *gin.Context) {
+		responsejson.InternalServerError(ctx, errors.New("database is down"))
+	})
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("boom")
+	})
+	return router, &buffer
+}
+
+func serveLogged(router *gin.Engine, path, requestId string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	if requestId != "" {
+		req.Header.Set(middleware.RequestIdHeader, requestId)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func logEntries(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	entries := []map[string]any{}
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		entry := map[string]any{}
+		assert.Nil(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestId(t *testing.T) {
+	t.Run("should keep a valid incoming request id", func(t *testing.T) {
+		router, buffer := setupLoggingRouter()
+
+		w := serveLogged(router, "/tag/1", "abc-123")
+
+		assert.Equal(t, "abc-123", w.Header().Get(middleware.RequestIdHeader))
+		assert.Equal(t, "abc-123", w.Body.String())
+		for _, entry := range logEntries(t, buffer) {
+			assert.Equal(t, "abc-123", entry["request_id"])
+		}
+	})
+
+	t.Run("should generate an id when none or an unsafe one is sent", func(t *testing.T) {
+		router, _ := setupLoggingRouter()
+
+		for _, incoming := range []string{"", "has spaces", string(make([]byte, 200))} {
+			w := serveLogged(router, "/tag/1", incoming)
+
+			id := w.Header().Get(middleware.RequestIdHeader)
+			assert.Len(t, id, 32)
+			assert.NotEqual(t, incoming, id)
+		}
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	t.Run("should log the request by route template", func(t *testing.T) {
+		router, buffer := setupLoggingRouter()
+
+		serveLogged(router, "/tag/7", "req-1")
+
+		entries := logEntries(t, buffer)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "handler", entries[0]["msg"])
+		access := entries[1]
+		assert.Equal(t, "request", access["msg"])
+		assert.Equal(t, "INFO", access["level"])
+		assert.Equal(t, "/tag/:tagId", access["route"])
+		assert.Equal(t, "/tag/7", access["path"])
+		assert.Equal(t, float64(http.StatusOK), access["status"])
+		assert.Equal(t, "req-1", access["request_id"])
+	})
+
+	t.Run("should log server errors with their cause", func(t *testing.T) {
+		router, buffer := setupLoggingRouter()
+
+		serveLogged(router, "/fail", "")
+
+		entries := logEntries(t, buffer)
+		assert.Len(t, entries, 1)
+		assert.Equal(t, "ERROR", entries[0]["level"])
+		assert.Contains(t, entries[0]["error"], "database is down")
+	})
+
+	t.Run("should recover from panics", func(t *testing.T) {
+		router, buffer := setupLoggingRouter()
+
+		w := serveLogged(router, "/panic", "req-2")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		entries := logEntries(t, buffer)
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "panic serving request", entries[0]["msg"])
+		assert.Equal(t, "boom", entries[0]["panic"])
+		assert.Equal(t, "req-2", entries[0]["request_id"])
+		assert.Equal(t, float64(http.StatusInternalServerError), entries[1]["status"])
+	})
+}
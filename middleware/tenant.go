@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"fmt"
+	"go-gin-project/helper"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/responsejson"
+	"go-gin-project/helper/tenant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Tenant resolves the tenant a request acts for and stores it in the request context. A tenant already placed
+// there by Authenticate, from a token claim or an API key, is authoritative. Callers authenticated without a tenant
+// may only name one in the tenant.Header header when they hold auth.ScopeTenantsAny, and are forbidden otherwise.
+// Requests without a valid tenant are rejected.
+func Tenant() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := tenant.FromContext(ctx.Request.Context()); ok {
+			ctx.Next()
+			return
+		}
+		if _, authenticated := ctx.Get(SubjectKey); authenticated && !auth.Grants(ctx.GetStringSlice(ScopesKey), auth.ScopeTenantsAny) {
+			responsejson.Forbidden(ctx, "credential names no tenant; choosing one with "+tenant.Header+" requires scope "+auth.ScopeTenantsAny)
+			ctx.Abort()
+			return
+		}
+
+		tenantId := ctx.GetHeader(tenant.Header)
+		if tenantId == "" {
+			responsejson.BadRequest(ctx, fmt.Errorf("%w: set the %s header", helper.ErrMissingTenant, tenant.Header))
+			ctx.Abort()
+			return
+		}
+		if !tenant.Valid(tenantId) {
+			responsejson.BadRequest(ctx, helper.ErrFailedValidationWrap(fmt.Errorf("invalid %s header", tenant.Header)))
+			ctx.Abort()
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), tenantId))
+		ctx.Next()
+	}
+}
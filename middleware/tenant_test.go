@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"go-gin-project/helper/tenant"
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTenantRouter(pre ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(pre...)
+	router.GET("/tag", middleware.Tenant(), func(ctx *gin.Context) {
+		tenantId, _ := tenant.FromContext(ctx.Request.Context())
+		ctx.String(http.StatusOK, tenantId)
+	})
+	return router
+}
+
+func serveTenant(router *gin.Engine, tenantId string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/tag", nil)
+	if tenantId != "" {
+		req.Header.Set(tenant.Header, tenantId)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTenant(t *testing.T) {
+	t.Run("should store the tenant from the header", func(t *testing.T) {
+		w := serveTenant(setupTenantRouter(), "team-a")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "team-a", w.Body.String())
+	})
+
+	t.Run("should reject a request without a tenant", func(t *testing.T) {
+		w := serveTenant(setupTenantRouter(), "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), tenant.Header)
+	})
+
+	t.Run("should reject an invalid tenant", func(t *testing.T) {
+		w := serveTenant(setupTenantRouter(), "team a")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "validation failed")
+	})
+
+	t.Run("should prefer a tenant resolved earlier", func(t *testing.T) {
+		resolved := func(ctx *gin.Context) {
+			ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), "from-claim"))
+		}
+		w := serveTenant(setupTenantRouter(resolved), "team-a")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "from-claim", w.Body.String())
+	})
+}
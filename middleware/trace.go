@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"go-gin-project/helper/logging"
+	"go-gin-project/helper/tracing"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace starts a server span for every request, continuing the trace of a W3C traceparent header when the caller
+// sent one. The span is named after the route template and placed in the request context, so the spans of the
+// service, repository and database become its children; the trace id is added to the request logger.
+func Trace(provider trace.TracerProvider) gin.HandlerFunc {
+	tracer := provider.Tracer(tracing.InstrumentationName)
+	propagator := propagation.TraceContext{}
+
+	return func(ctx *gin.Context) {
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		parent := propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		spanCtx, span := tracer.Start(parent, ctx.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(ctx.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		if span.SpanContext().IsValid() {
+			logger := logging.FromContext(spanCtx).With(slog.String("trace_id", span.SpanContext().TraceID().String()))
+			spanCtx = logging.NewContext(spanCtx, logger)
+		}
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if err := ctx.Errors.Last(); err != nil {
+			span.RecordError(err.Err)
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+		}
+	}
+}
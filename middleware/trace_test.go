@@ -0,0 +1,78 @@
+package middleware_test
+
+import (
+	"go-gin-project/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupTraceRouter() (*tracetest.SpanRecorder, *gin.Engine) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Trace(provider))
+	router.GET("/tag/:tagId", func(ctx *gin.Context) {
+		_, child := provider.Tracer("test").Start(ctx.Request.Context(), "child")
+		child.End()
+		if ctx.Param("tagId") == "fail" {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	})
+	return recorder, router
+}
+
+func TestTrace(t *testing.T) {
+	t.Run("should continue the trace of the caller", func(t *testing.T) {
+		recorder, router := setupTraceRouter()
+		req, _ := http.NewRequest("GET", "/tag/1", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		child, server := spans[0], spans[1]
+		assert.Equal(t, "GET /tag/:tagId", server.Name())
+		assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		assert.True(t, server.Parent().IsRemote())
+		assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+		assert.Equal(t, codes.Unset, server.Status().Code)
+	})
+
+	t.Run("should start a new trace without a traceparent", func(t *testing.T) {
+		recorder, router := setupTraceRouter()
+		req, _ := http.NewRequest("GET", "/tag/1", nil)
+		req.Header.Set("traceparent", "malformed")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		server := recorder.Ended()[1]
+		assert.True(t, server.SpanContext().IsValid())
+		assert.False(t, server.Parent().IsValid())
+	})
+
+	t.Run("should mark server errors and unmatched routes", func(t *testing.T) {
+		recorder, router := setupTraceRouter()
+		for _, path := range []string{"/tag/fail", "/unknown/3"} {
+			req, _ := http.NewRequest("GET", path, nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 3)
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Equal(t, "GET unmatched", spans[2].Name())
+		assert.Equal(t, codes.Unset, spans[2].Status().Code)
+	})
+}
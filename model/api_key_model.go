@@ -0,0 +1,77 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ApiKey authenticates a service. Only the hash of its secret is stored; the prefix identifies it.
+// Keys are not tenant scoped; a key with a TenantId acts for that tenant.
+type ApiKey struct {
+	Id         int          `gorm:"type:int;primary_key"`
+	Name       string       `gorm:"type:varchar(255);not null"`
+	Prefix     string       `gorm:"type:varchar(32);not null;uniqueIndex"`
+	SecretHash string       `gorm:"type:varchar(64);not null"`
+	Scopes     ApiKeyScopes `gorm:"column:scopes"`
+	TenantId   string       `gorm:"type:varchar(64)"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time `gorm:"index"`
+	CreatedAt  time.Time
+}
+
+// Usable reports whether the key may authenticate at the given time.
+func (k ApiKey) Usable(at time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || at.Before(*k.ExpiresAt))
+}
+
+// ApiKeyScopes lists the scopes granted to a key, stored as a JSON array.
+type ApiKeyScopes []string
+
+func (ApiKeyScopes) GormDataType() string {
+	return "json"
+}
+
+func (ApiKeyScopes) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "jsonb"
+	}
+	return "text"
+}
+
+func (s ApiKeyScopes) Value() (driver.Value, error) {
+	if s == nil {
+		s = ApiKeyScopes{}
+	}
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (s *ApiKeyScopes) Scan(value any) error {
+	var encoded []byte
+	switch v := value.(type) {
+	case nil:
+		*s = nil
+		return nil
+	case []byte:
+		encoded = v
+	case string:
+		encoded = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ApiKeyScopes", value)
+	}
+	scopes := ApiKeyScopes{}
+	if err := json.Unmarshal(encoded, &scopes); err != nil {
+		return err
+	}
+	*s = scopes
+	return nil
+}
@@ -1,7 +1,174 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// migratedModels lists the models Migration keeps in the schema, tags first.
+var migratedModels = []any{&Tags{}, &TagAlias{}, &Tagging{}, &Todo{}, &TodoTag{}, &ApiKey{}}
 
 func Migration(db *gorm.DB) error {
-	return db.Table("tags").AutoMigrate(&Tags{})
+	if err := backfillNormalizedTagNames(db); err != nil {
+		return err
+	}
+	if err := mergeDuplicateTagNames(db); err != nil {
+		return err
+	}
+	if err := dropLegacyIndexes(db); err != nil {
+		return err
+	}
+	if err := db.Table("tags").AutoMigrate(&Tags{}); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(migratedModels[1:]...); err != nil {
+		return err
+	}
+	return backfillTagTimestamps(db)
+}
+
+// backfillNormalizedTagNames fills the column for rows created before it existed, so the unique index can be built.
+func backfillNormalizedTagNames(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Tags{}) || migrator.HasColumn(&Tags{}, "NormalizedName") {
+		return nil
+	}
+	if err := migrator.AddColumn(&Tags{}, "NormalizedName"); err != nil {
+		return err
+	}
+	return db.Exec("UPDATE tags SET normalized_name = LOWER(TRIM(name))").Error
+}
+
+// mergeDuplicateTagNames folds active tags whose names only differ in case or surrounding spaces into the oldest
+// of them, the one with the lowest id, so the unique index on normalized names can be built. Todo links, taggings,
+// aliases and children of the duplicates move to the kept tag and the duplicates go to the trash.
+func mergeDuplicateTagNames(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&Tags{}) || migrator.HasIndex(&Tags{}, "idx_tags_tenant_normalized_name") {
+		return nil
+	}
+	if !migrator.HasColumn(&Tags{}, "DeletedAt") {
+		if err := migrator.AddColumn(&Tags{}, "DeletedAt"); err != nil {
+			return err
+		}
+	}
+
+	group, join := "normalized_name", "kept.normalized_name = tags.normalized_name"
+	if migrator.HasColumn(&Tags{}, "TenantId") {
+		group, join = "tenant_id, normalized_name", join+" AND kept.tenant_id = tags.tenant_id"
+	}
+	var duplicates []struct {
+		Id     int
+		KeptId int
+	}
+	result := db.Raw(`SELECT tags.id, kept.id AS kept_id FROM tags
+		JOIN (
+			SELECT MIN(id) AS id, ` + group + ` FROM tags WHERE deleted_at IS NULL
+			GROUP BY ` + group + ` HAVING COUNT(*) > 1
+		) kept ON ` + join + `
+		WHERE tags.deleted_at IS NULL AND tags.id <> kept.id
+		ORDER BY tags.id`).Scan(&duplicates)
+	if result.Error != nil || len(duplicates) == 0 {
+		return result.Error
+	}
+
+	hasTodoTags, hasTaggings := migrator.HasTable(&TodoTag{}), migrator.HasTable(&Tagging{})
+	hasAliases, hasParents := migrator.HasTable(&TagAlias{}), migrator.HasColumn(&Tags{}, "ParentId")
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, duplicate := range duplicates {
+			statements := []string{}
+			if hasTodoTags {
+				statements = append(statements,
+					"DELETE FROM todo_tags WHERE tag_id = @id AND todo_id IN (SELECT todo_id FROM todo_tags WHERE tag_id = @kept)",
+					"UPDATE todo_tags SET tag_id = @kept WHERE tag_id = @id")
+			}
+			if hasTaggings {
+				statements = append(statements,
+					`DELETE FROM taggings WHERE tag_id = @id AND EXISTS (
+						SELECT 1 FROM taggings kept WHERE kept.tag_id = @kept
+						AND kept.resource_type = taggings.resource_type AND kept.resource_id = taggings.resource_id
+					)`,
+					"UPDATE taggings SET tag_id = @kept WHERE tag_id = @id")
+			}
+			if hasAliases {
+				statements = append(statements, "UPDATE tag_aliases SET tag_id = @kept WHERE tag_id = @id")
+			}
+			if hasParents {
+				statements = append(statements, "UPDATE tags SET parent_id = @kept WHERE parent_id = @id")
+			}
+			statements = append(statements, "UPDATE tags SET deleted_at = CURRENT_TIMESTAMP WHERE id = @id")
+
+			for _, statement := range statements {
+				err := tx.Exec(statement, sql.Named("id", duplicate.Id), sql.Named("kept", duplicate.KeptId)).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// legacyIndexes were replaced by indexes that skip trashed tags or lead with the tenant. The replacements have new
+// names, so the old indexes are dropped here rather than left to AutoMigrate.
+var legacyIndexes = []struct {
+	model any
+	name  string
+}{
+	{&Tags{}, "idx_tags_normalized_name"},
+	{&Tags{}, "idx_tags_active_normalized_name"},
+	{&TagAlias{}, "idx_tag_aliases_normalized_name"},
+	{&Tagging{}, "idx_taggings_resource"},
+}
+
+func dropLegacyIndexes(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, index := range legacyIndexes {
+		if !migrator.HasTable(index.model) || !migrator.HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := migrator.DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillTagTimestamps stamps rows created before the timestamp columns existed with the migration time.
+func backfillTagTimestamps(db *gorm.DB) error {
+	return db.Exec("UPDATE tags SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE created_at IS NULL").Error
+}
+
+// PendingMigrations lists the tables and columns of the migrated models that the database lacks, as "table" or
+// "table.column". An empty list means Migration has brought the schema up to date.
+func PendingMigrations(db *gorm.DB) ([]string, error) {
+	migrator := db.Migrator()
+	pending := []string{}
+	for _, model := range migratedModels {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(model); err != nil {
+			return nil, err
+		}
+		table := statement.Schema.Table
+		if !migrator.HasTable(table) {
+			pending = append(pending, table)
+			continue
+		}
+
+		columnTypes, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return nil, err
+		}
+		columns := map[string]bool{}
+		for _, columnType := range columnTypes {
+			columns[columnType.Name()] = true
+		}
+		for _, field := range statement.Schema.Fields {
+			if field.DBName != "" && !field.IgnoreMigration && !columns[field.DBName] {
+				pending = append(pending, table+"."+field.DBName)
+			}
+		}
+	}
+	return pending, nil
 }
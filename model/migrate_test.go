@@ -0,0 +1,93 @@
+package model_test
+
+import (
+	"go-gin-project/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.Nil(t, err)
+
+	pending, err := model.PendingMigrations(db)
+	assert.Nil(t, err)
+	assert.Contains(t, pending, "tags")
+	assert.Contains(t, pending, "api_keys")
+
+	assert.Nil(t, model.Migration(db))
+	pending, err = model.PendingMigrations(db)
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+
+	assert.Nil(t, db.Migrator().DropColumn(&model.Todo{}, "Priority"))
+	pending, err = model.PendingMigrations(db)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"todos.priority"}, pending)
+}
+
+func TestMigrationMergesDuplicateTagNames(t *testing.T) {
+	t.Run("should migrate a baseline table holding case duplicates", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		assert.Nil(t, err)
+		assert.Nil(t, db.Exec("CREATE TABLE tags (id integer PRIMARY KEY, name varchar(255))").Error)
+		assert.Nil(t, db.Exec("INSERT INTO tags (id, name) VALUES (1, 'golang'), (2, 'Golang'), (3, ' GOLANG '), (4, 'rust')").Error)
+
+		assert.Nil(t, model.Migration(db))
+
+		var active []model.Tags
+		assert.Nil(t, db.Order("id").Find(&active).Error)
+		assert.Len(t, active, 2)
+		assert.Equal(t, "golang", active[0].Name)
+		assert.Equal(t, "rust", active[1].Name)
+
+		var trashed int64
+		assert.Nil(t, db.Unscoped().Model(&model.Tags{}).Where("deleted_at IS NOT NULL").Count(&trashed).Error)
+		assert.Equal(t, int64(2), trashed)
+	})
+
+	t.Run("should move links of the duplicates to the oldest tag", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		assert.Nil(t, err)
+		assert.Nil(t, model.Migration(db))
+
+		// A deployment whose earlier migration stopped before the unique index was built.
+		assert.Nil(t, db.Migrator().DropIndex(&model.Tags{}, "idx_tags_tenant_normalized_name"))
+		assert.Nil(t, db.Exec(`INSERT INTO tags (id, tenant_id, name, normalized_name, parent_id, version) VALUES
+			(1, 'default', 'golang', 'golang', NULL, 1),
+			(2, 'default', 'Golang', 'golang', NULL, 1),
+			(3, 'default', 'gin', 'gin', 2, 1),
+			(4, 'other', 'GoLang', 'golang', NULL, 1)`).Error)
+		assert.Nil(t, db.Exec("INSERT INTO todos (id, tenant_id, title) VALUES (1, 'default', 'a'), (2, 'default', 'b')").Error)
+		assert.Nil(t, db.Exec("INSERT INTO todo_tags (todo_id, tag_id) VALUES (1, 1), (1, 2), (2, 2)").Error)
+		assert.Nil(t, db.Exec(`INSERT INTO taggings (tenant_id, tag_id, resource_type, resource_id) VALUES
+			('default', 1, 'article', 'a-1'), ('default', 2, 'article', 'a-1'), ('default', 2, 'article', 'a-2')`).Error)
+		assert.Nil(t, db.Exec("INSERT INTO tag_aliases (tenant_id, tag_id, name, normalized_name) VALUES ('default', 2, 'go', 'go')").Error)
+
+		assert.Nil(t, model.Migration(db))
+		assert.True(t, db.Migrator().HasIndex(&model.Tags{}, "idx_tags_tenant_normalized_name"))
+
+		var active []int
+		assert.Nil(t, db.Model(&model.Tags{}).Order("id").Pluck("id", &active).Error)
+		assert.Equal(t, []int{1, 3, 4}, active)
+
+		var todoTags []model.TodoTag
+		assert.Nil(t, db.Order("todo_id").Find(&todoTags).Error)
+		assert.Equal(t, []model.TodoTag{{TodoId: 1, TagId: 1}, {TodoId: 2, TagId: 1}}, todoTags)
+
+		var resources []string
+		assert.Nil(t, db.Model(&model.Tagging{}).Where("tag_id = ?", 1).Order("resource_id").Pluck("resource_id", &resources).Error)
+		assert.Equal(t, []string{"a-1", "a-2"}, resources)
+
+		var alias model.TagAlias
+		assert.Nil(t, db.First(&alias).Error)
+		assert.Equal(t, 1, alias.TagId)
+
+		var gin model.Tags
+		assert.Nil(t, db.First(&gin, 3).Error)
+		assert.Equal(t, 1, *gin.ParentId)
+	})
+}
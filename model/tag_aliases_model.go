@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagAlias is an alternative name that resolves to its canonical tag.
+type TagAlias struct {
+	Id             int    `gorm:"type:int;primary_key"`
+	TenantId       string `gorm:"type:varchar(64);not null;default:default;uniqueIndex:idx_tag_aliases_tenant_normalized_name,priority:1"`
+	TagId          int    `gorm:"not null;index"`
+	Name           string `gorm:"type:varchar(255)"`
+	NormalizedName string `gorm:"type:varchar(255);uniqueIndex:idx_tag_aliases_tenant_normalized_name,priority:2"`
+	CreatedAt      time.Time
+}
+
+func (a *TagAlias) BeforeSave(tx *gorm.DB) error {
+	if a.TenantId == "" {
+		a.TenantId = contextTenant(tx)
+	}
+	if a.Name != "" {
+		tx.Statement.SetColumn("NormalizedName", NormalizeTagName(a.Name))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TagAttributes is a free-form JSON object attached to a tag. It is stored as jsonb on Postgres and as TEXT elsewhere.
+type TagAttributes map[string]any
+
+func (TagAttributes) GormDataType() string {
+	return "json"
+}
+
+func (TagAttributes) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	if db.Dialector.Name() == "postgres" {
+		return "jsonb"
+	}
+	return "text"
+}
+
+func (a TagAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func (a *TagAttributes) Scan(value any) error {
+	var encoded []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		encoded = v
+	case string:
+		encoded = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into TagAttributes", value)
+	}
+	attributes := TagAttributes{}
+	if err := json.Unmarshal(encoded, &attributes); err != nil {
+		return err
+	}
+	*a = attributes
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tagging attaches a tag to a resource owned elsewhere, identified by an opaque type and id.
+type Tagging struct {
+	Id           int       `gorm:"type:int;primary_key"`
+	TenantId     string    `gorm:"type:varchar(64);not null;default:default;index:idx_taggings_tenant_resource,priority:1"`
+	TagId        int       `gorm:"not null;uniqueIndex:idx_taggings_tag_resource"`
+	ResourceType string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_taggings_tag_resource;index:idx_taggings_tenant_resource"`
+	ResourceId   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_taggings_tag_resource;index:idx_taggings_tenant_resource"`
+	CreatedAt    time.Time `gorm:"index"`
+}
+
+func (t *Tagging) BeforeCreate(tx *gorm.DB) error {
+	if t.TenantId == "" {
+		t.TenantId = contextTenant(tx)
+	}
+	return nil
+}
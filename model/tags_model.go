@@ -1,6 +1,67 @@
 package model
 
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Tags struct {
-	Id   int    `gorm:"type:int;primary_key"`
-	Name string `gorm:"type:varchar(255)"`
-}
\ No newline at end of file
+	Id             int            `gorm:"type:int;primary_key"`
+	TenantId       string         `gorm:"type:varchar(64);not null;default:default;uniqueIndex:idx_tags_tenant_normalized_name,priority:1,where:deleted_at IS NULL"`
+	Name           string         `gorm:"type:varchar(255)"`
+	NormalizedName string         `gorm:"type:varchar(255);uniqueIndex:idx_tags_tenant_normalized_name,priority:2,where:deleted_at IS NULL"`
+	ParentId       *int           `gorm:"index"`
+	Description    string         `gorm:"type:text"`
+	Color          string         `gorm:"type:varchar(9)"`
+	Icon           string         `gorm:"type:varchar(64)"`
+	Attributes     TagAttributes  `gorm:"column:attributes"`
+	Version        int            `gorm:"not null;default:1"`
+	CreatedAt      time.Time      `gorm:"index"`
+	UpdatedAt      time.Time      `gorm:"index"`
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	Aliases        []TagAlias     `gorm:"foreignKey:TagId"`
+}
+
+// ErrAliasTaken is returned when a tag would take a name that is already an alias of another tag.
+var ErrAliasTaken = errors.New("name is an alias of another tag")
+
+// BeforeSave keeps NormalizedName in sync with Name so the unique index treats "Golang" and "golang" as the same tag,
+// and refuses names that already resolve to another tag of the tenant through an alias.
+func (t *Tags) BeforeSave(tx *gorm.DB) error {
+	if t.TenantId == "" {
+		t.TenantId = contextTenant(tx)
+	}
+	if t.Name == "" {
+		return nil
+	}
+	normalizedName := NormalizeTagName(t.Name)
+	tx.Statement.SetColumn("NormalizedName", normalizedName)
+
+	var count int64
+	result := tx.Session(&gorm.Session{NewDB: true}).
+		Model(&TagAlias{}).
+		Where("tenant_id = ? AND normalized_name = ? AND tag_id <> ?", t.TenantId, normalizedName, t.Id).
+		Count(&count)
+	if result.Error != nil {
+		return result.Error
+	}
+	if count > 0 {
+		return ErrAliasTaken
+	}
+	return nil
+}
+
+// BeforeCreate starts every tag at version 1; updates bump it so stale writers can be detected.
+func (t *Tags) BeforeCreate(tx *gorm.DB) error {
+	if t.Version == 0 {
+		t.Version = 1
+	}
+	return nil
+}
+
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"go-gin-project/helper/tenant"
+
+	"gorm.io/gorm"
+)
+
+// contextTenant is the tenant of the statement's context; rows written without one fall back to the column default.
+func contextTenant(tx *gorm.DB) string {
+	id, _ := tenant.FromContext(tx.Statement.Context)
+	return id
+}
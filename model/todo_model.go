@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Todo struct {
+	Id        int        `gorm:"type:int;primary_key"`
+	TenantId  string     `gorm:"type:varchar(64);not null;default:default;index"`
+	Title     string     `gorm:"type:varchar(255);not null"`
+	Status    string     `gorm:"type:varchar(20);not null;default:open;index"`
+	Priority  int        `gorm:"not null;default:0;index"`
+	DueDate   *time.Time `gorm:"index"`
+	Tags      []Tags     `gorm:"many2many:todo_tags;joinForeignKey:TodoId;joinReferences:TagId"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (t *Todo) BeforeCreate(tx *gorm.DB) error {
+	if t.TenantId == "" {
+		t.TenantId = contextTenant(tx)
+	}
+	return nil
+}
+
+// TodoTag is the join row between a todo and one of its tags.
+type TodoTag struct {
+	TodoId int `gorm:"primaryKey"`
+	TagId  int `gorm:"primaryKey;index"`
+}
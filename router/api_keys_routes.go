@@ -0,0 +1,18 @@
+package router
+
+import (
+	"go-gin-project/api/controller"
+	"go-gin-project/helper/auth"
+	"go-gin-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ApiKeysRouter(router *gin.Engine, controller *controller.ApiKeysController, limits RateLimits) {
+	apiKeysRouter := router.Group("/admin/api-key", limits.group("api-key"), middleware.RequireScope(auth.ScopeApiKeysAdmin))
+	{
+		apiKeysRouter.GET("", controller.FindAll)
+		apiKeysRouter.POST("", controller.Create)
+		apiKeysRouter.DELETE("/:keyId", controller.Revoke)
+	}
+}
@@ -0,0 +1,13 @@
+package router
+
+import (
+	"go-gin-project/api/controller"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRouter serves the probes of the orchestrator. They are neither authenticated nor rate limited.
+func HealthRouter(router *gin.Engine, controller *controller.HealthController) {
+	router.GET("/healthz", controller.Liveness)
+	router.GET("/readyz", controller.Readiness)
+}
@@ -1,19 +1,60 @@
 package router
 
 import (
+	"go-gin-project/api/controller"
+	"go-gin-project/config"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/helper/ratelimit"
+	"go-gin-project/middleware"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func SetupRouter() *gin.Engine {
-	router := gin.Default()
+// Controllers are the handlers NewRouter mounts.
+type Controllers struct {
+	Tags    *controller.TagsController
+	Todo    *controller.TodoController
+	ApiKeys *controller.ApiKeysController
+	Health  *controller.HealthController
+}
+
+// NewRouter mounts every route. Requests log through logger. Client addresses are taken from forwarding headers
+// only when the peer is one of proxies, and every request, authenticated or not, counts against the budget of its
+// address first.
+func NewRouter(controllers Controllers, verifier *auth.Verifier, apiKeys middleware.ApiKeyAuthenticator, metrics *metrics.Metrics,
+	tracerProvider trace.TracerProvider, limits RateLimits, proxies config.TrustedProxies, logger *slog.Logger) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		return nil, err
+	}
+	router.Use(middleware.RequestId(logger), middleware.AccessLog(), middleware.Metrics(metrics),
+		middleware.Trace(tracerProvider), middleware.Recover())
+	router.Use(middleware.RateLimitByIP(limits.Store, limits.Config.For(middleware.IPGroup)))
+	router.Use(middleware.Authenticate(verifier, apiKeys, "/ping", "/metrics", "/healthz", "/readyz"))
 
 	router.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "pong"})
 	})
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	HealthRouter(router, controllers.Health)
+
+	TagsRouter(router, controllers.Tags, limits)
+	TodoRouter(router, controllers.Todo, limits)
+	ApiKeysRouter(router, controllers.ApiKeys, limits)
 
-	TagsRouter(router)
+	return router, nil
+}
+
+// RateLimits limits route groups with the limits configured for them.
+type RateLimits struct {
+	Store  ratelimit.Store
+	Config config.RateLimitConfig
+}
 
-	return router
+func (l RateLimits) group(name string) gin.HandlerFunc {
+	return middleware.RateLimit(l.Store, name, l.Config.For(name))
 }
@@ -0,0 +1,40 @@
+package router_test
+
+import (
+	"bytes"
+	"go-gin-project/config"
+	"go-gin-project/helper/auth"
+	"go-gin-project/helper/metrics"
+	"go-gin-project/helper/ratelimit"
+	"go-gin-project/router"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNewRouter(t *testing.T) {
+	t.Run("should log requests through the logger it is given", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		verifier, err := auth.NewVerifier(auth.Options{Secret: []byte("secret")})
+		assert.Nil(t, err)
+		var logs bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+		engine, err := router.NewRouter(router.Controllers{}, verifier, nil, metrics.New(), noop.NewTracerProvider(),
+			router.RateLimits{Store: ratelimit.NewMemoryStore(), Config: config.Default().RateLimit}, nil, logger)
+		assert.Nil(t, err)
+
+		req, _ := http.NewRequest("GET", "/ping", nil)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, logs.String(), `"path":"/ping"`)
+		assert.Contains(t, logs.String(), w.Header().Get("X-Request-ID"))
+	})
+}
@@ -1,20 +1,47 @@
 package router
 
 import (
-	"go-gin-project/api"
+	"go-gin-project/api/controller"
+	"go-gin-project/helper/auth"
+	"go-gin-project/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
-func TagsRouter(router *gin.Engine) {
-	controller := api.InitializeTagsController()
+func TagsRouter(router *gin.Engine, controller *controller.TagsController, limits RateLimits) {
+	read := middleware.RequireScope(auth.ScopeTagsRead)
+	write := middleware.RequireScope(auth.ScopeTagsWrite)
+	admin := middleware.RequireScope(auth.ScopeTagsAdmin)
 
-	tagsRouter := router.Group("/tag")
+	tagsRouter := router.Group("/tag", limits.group("tag"), middleware.Tenant())
 	{
-		tagsRouter.GET("", controller.FindAll)
-		tagsRouter.GET("/:tagId", controller.FindById)
-		tagsRouter.POST("", controller.Create)
-		tagsRouter.PUT("/:tagId", controller.Update)
-		tagsRouter.DELETE("/:tagId", controller.Delete)
+		tagsRouter.GET("", read, controller.FindAll)
+		tagsRouter.GET("/tree", read, controller.FindTree)
+		tagsRouter.GET("/trash", read, controller.FindTrash)
+		tagsRouter.DELETE("/trash", admin, controller.PurgeTrash)
+		tagsRouter.POST("/bulk", write, controller.BulkCreate)
+		tagsRouter.PUT("/bulk", write, controller.BulkUpdate)
+		tagsRouter.DELETE("/bulk", admin, controller.BulkDelete)
+		tagsRouter.GET("/name/:name", read, controller.FindByName)
+		tagsRouter.GET("/:tagId", read, controller.FindById)
+		tagsRouter.GET("/:tagId/children", read, controller.FindChildren)
+		tagsRouter.GET("/:tagId/ancestors", read, controller.FindAncestors)
+		tagsRouter.GET("/:tagId/resources", read, controller.FindResources)
+		tagsRouter.POST("", write, controller.Create)
+		tagsRouter.POST("/:tagId/restore", write, controller.Restore)
+		tagsRouter.POST("/:tagId/move", write, controller.Move)
+		tagsRouter.POST("/:tagId/merge", admin, controller.Merge)
+		tagsRouter.POST("/:tagId/aliases", write, controller.AddAlias)
+		tagsRouter.DELETE("/:tagId/aliases/:alias", write, controller.DeleteAlias)
+		tagsRouter.PUT("/:tagId", write, controller.Update)
+		tagsRouter.PATCH("/:tagId", write, controller.Patch)
+		tagsRouter.DELETE("/:tagId", admin, controller.Delete)
+	}
+
+	resourcesRouter := router.Group("/resources/:resourceType/:resourceId/tags", limits.group("resources"), middleware.Tenant())
+	{
+		resourcesRouter.GET("", read, controller.FindByResource)
+		resourcesRouter.PUT("/:tagId", write, controller.Attach)
+		resourcesRouter.DELETE("/:tagId", write, controller.Detach)
 	}
 }
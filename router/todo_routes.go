@@ -0,0 +1,23 @@
+package router
+
+import (
+	"go-gin-project/api/controller"
+	"go-gin-project/helper/auth"
+	"go-gin-project/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TodoRouter(router *gin.Engine, controller *controller.TodoController, limits RateLimits) {
+	read := middleware.RequireScope(auth.ScopeTodosRead)
+	write := middleware.RequireScope(auth.ScopeTodosWrite)
+
+	todoRouter := router.Group("/todo", limits.group("todo"), middleware.Tenant())
+	{
+		todoRouter.GET("", read, controller.FindAll)
+		todoRouter.GET("/:todoId", read, controller.FindById)
+		todoRouter.POST("", write, controller.Create)
+		todoRouter.PUT("/:todoId", write, controller.Update)
+		todoRouter.DELETE("/:todoId", write, controller.Delete)
+	}
+}
@@ -0,0 +1,59 @@
+package router_test
+
+import (
+	"go-gin-project/api/controller"
+	"go-gin-project/config"
+	"go-gin-project/helper/ratelimit"
+	"go-gin-project/helper/tenant"
+	"go-gin-project/middleware"
+	"go-gin-project/router"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupTodoRouter mounts the todo routes for a caller of tenant "team-a" holding scopes. The controller has no
+// service, so only requests stopped before it can be served.
+func setupTodoRouter(scopes ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		ctx.Set(middleware.SubjectKey, "user-1")
+		ctx.Set(middleware.ScopesKey, scopes)
+		ctx.Request = ctx.Request.WithContext(tenant.NewContext(ctx.Request.Context(), "team-a"))
+	})
+	router.TodoRouter(engine, controller.NewTodoController(nil), router.RateLimits{
+		Store:  ratelimit.NewMemoryStore(),
+		Config: config.Default().RateLimit,
+	})
+	return engine
+}
+
+func TestTodoRouter(t *testing.T) {
+	t.Run("should require a todo scope on every route", func(t *testing.T) {
+		for _, target := range [][2]string{
+			{"GET", "/todo"}, {"GET", "/todo/1"}, {"POST", "/todo"}, {"PUT", "/todo/1"}, {"DELETE", "/todo/1"},
+		} {
+			req, _ := http.NewRequest(target[0], target[1], nil)
+			w := httptest.NewRecorder()
+			setupTodoRouter("tags:admin").ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, target)
+			assert.Contains(t, w.Body.String(), "requires scope todos:", target)
+		}
+	})
+
+	t.Run("should not let readers write", func(t *testing.T) {
+		for _, target := range [][2]string{{"POST", "/todo"}, {"PUT", "/todo/1"}, {"DELETE", "/todo/1"}} {
+			req, _ := http.NewRequest(target[0], target[1], nil)
+			w := httptest.NewRecorder()
+			setupTodoRouter("todos:read").ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code, target)
+			assert.Contains(t, w.Body.String(), "requires scope todos:write", target)
+		}
+	})
+}